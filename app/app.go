@@ -3,13 +3,22 @@ package app
 import (
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
+	"github.com/shyandsy/aurora/bizerr"
 	"github.com/shyandsy/aurora/config"
 	"github.com/shyandsy/aurora/contracts"
 
 	"github.com/shyandsy/di"
 )
 
+// Commands run by App.RunCommand
+const (
+	CommandOpenAPI = "openapi"
+	CommandErrors  = "errors"
+)
+
 type appConfig struct {
 	Server config.ServerConfig
 }
@@ -65,6 +74,14 @@ func (a *app) RegisterRoutes(routes []contracts.Route) {
 	a.serverFeature.RegisterRoutes(routes)
 }
 
+func (a *app) RegisterWebSocketRoutes(routes []contracts.WebSocketRoute) {
+	a.serverFeature.RegisterWebSocketRoutes(routes)
+}
+
+func (a *app) RegisterStaticSites(sites []contracts.StaticSite) {
+	a.serverFeature.RegisterStaticSites(sites)
+}
+
 func (a *app) registerBaseDependencies() {
 	if err := a.Provide(&a.config.Server); err != nil {
 		log.Fatalf("Failed to register ServerConfig: %v", err)
@@ -91,13 +108,98 @@ func (a *app) Shutdown() error {
 	return nil
 }
 
+// RunCommand runs the command named by args[0] instead of the server and
+// reports whether args named one. Commands only need the server feature and
+// the routes, so it is called before adding features that connect to
+// databases or run migrations.
+func (a *app) RunCommand(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	switch args[0] {
+	case CommandOpenAPI:
+		return true, a.writeOpenAPI(args[1:])
+	case CommandErrors:
+		return true, a.writeErrorCatalogue(args[1:])
+	}
+	return false, nil
+}
+
+// writeOpenAPI handles the "openapi [file]" command: it writes the OpenAPI
+// document of the registered routes to file, or to stdout, without starting the server.
+func (a *app) writeOpenAPI(args []string) error {
+	if a.serverFeature == nil {
+		return fmt.Errorf("the %s command requires the server feature", CommandOpenAPI)
+	}
+	data, err := a.serverFeature.OpenAPI()
+	if err != nil {
+		return fmt.Errorf("failed to generate OpenAPI document: %w", err)
+	}
+
+	if len(args) == 0 || args[0] == "-" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+
+	if err := os.WriteFile(args[0], append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write OpenAPI document: %w", err)
+	}
+	log.Printf("OpenAPI document written to %s", args[0])
+	return nil
+}
+
+// writeErrorCatalogue handles the "errors [json|markdown] [file]" command: it
+// writes the bizerr error catalogue to file, or to stdout, as JSON or as a
+// markdown table. Files ending in .md default to markdown.
+func (a *app) writeErrorCatalogue(args []string) error {
+	format := ""
+	if len(args) > 0 && (args[0] == "json" || args[0] == "markdown") {
+		format, args = args[0], args[1:]
+	}
+	file := "-"
+	if len(args) > 0 {
+		file = args[0]
+	}
+	if format == "" {
+		format = "json"
+		if strings.HasSuffix(file, ".md") {
+			format = "markdown"
+		}
+	}
+
+	var data []byte
+	if format == "markdown" {
+		data = []byte(bizerr.CatalogueMarkdown())
+	} else {
+		var err error
+		if data, err = bizerr.CatalogueJSON(); err != nil {
+			return fmt.Errorf("failed to export error catalogue: %w", err)
+		}
+		data = append(data, '\n')
+	}
+
+	if file == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("failed to write error catalogue: %w", err)
+	}
+	log.Printf("Error catalogue written to %s", file)
+	return nil
+}
+
 func (a *app) printStartupInfo() {
 	fmt.Println("########################################################")
 	fmt.Printf("#\t 🏠 Aurora Framework\n")
 	fmt.Printf("#\t Service: %s\n", a.config.Server.Name)
 	fmt.Printf("#\t Version: %s\n", a.config.Server.Version)
 	fmt.Printf("#\t RunLevel: %s\n", a.config.Server.RunLevel)
-	fmt.Printf("#\t Address: %s:%d\n", a.config.Server.Host, a.config.Server.Port)
+	if a.config.Server.Listen != "" {
+		fmt.Printf("#\t Listen: %s\n", a.config.Server.Listen)
+	} else {
+		fmt.Printf("#\t Address: %s:%d\n", a.config.Server.Host, a.config.Server.Port)
+	}
 	fmt.Printf("#\t GinMode: %s\n", a.config.Server.GinMode())
 	fmt.Println("########################################################")
 	fmt.Println()
@@ -2,7 +2,6 @@ package bizerr
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 )
 
@@ -24,30 +23,56 @@ func (v ValidationError) Error() string {
 
 type BizError interface {
 	HTTPCode() int
+	// Code is the machine-readable code of the error, see Definition
+	Code() string
+	// ErrorNo is the numeric code of the error, zero when it has none
+	ErrorNo() int
+	// MessageID is the i18n message ID of the message, empty when the message is not translated
+	MessageID() string
+	// Message is the message sent to clients
 	Message() string
+	// Error is the message followed by the cause, for logs
 	Error() string
+	// Unwrap returns the cause, so that errors.Is and errors.As see through the error
+	Unwrap() error
 	ValidationErrors() map[string]string
 	IsValidationError() bool
 }
 
 type bizError struct {
-	httpCode        int
-	errorNo         int
-	err             error
+	httpCode  int
+	errorNo   int
+	code      string
+	messageID string
+	message   string
+	cause     error
+	stack     []uintptr
+	// defined is set for errors of a registered Definition, which are equal by code
+	defined         bool
 	validationError *ValidationError
 }
 
+// New creates an error with a status and a code derived from it, e.g.
+// "not_found" for 404, with the message of err. The message of server errors
+// (5xx) is the status text, err is only logged. Use a registered Definition
+// for specific codes, and Wrap to keep err out of the client message.
 func New(httpCode int, err error) BizError {
 	return &bizError{
 		httpCode: httpCode,
-		err:      err,
+		code:     statusCode(httpCode),
+		message:  clientMessage(err, httpCode),
+		cause:    err,
+		stack:    callers(0),
 	}
 }
 
 func NewValidationError(message string, fields map[string]string) BizError {
 	return &bizError{
 		httpCode: http.StatusBadRequest,
-		err:      errors.New(message),
+		code:     CodeValidationFailed.Code,
+		message:  message,
+		stack:    callers(0),
+		defined:  true,
 		validationError: &ValidationError{
 			Message: message,
 			Fields:  fields,
@@ -59,15 +84,38 @@ func (b bizError) HTTPCode() int {
 	return b.httpCode
 }
 
+func (b bizError) Code() string {
+	return b.code
+}
+
+func (b bizError) ErrorNo() int {
+	return b.errorNo
+}
+
+func (b bizError) MessageID() string {
+	return b.messageID
+}
+
 func (b bizError) Message() string {
-	if b.err != nil {
-		return b.err.Error()
-	}
-	return ""
+	return b.message
 }
 
 func (b bizError) Error() string {
-	return b.err.Error()
+	if b.cause != nil && b.cause.Error() != b.message {
+		return b.message + ": " + b.cause.Error()
+	}
+	return b.message
+}
+
+func (b bizError) Unwrap() error {
+	return b.cause
+}
+
+// Is matches errors of the same registered Definition, e.g.
+// errors.Is(err, bizerr.ErrNotFound()). Other errors are only equal to themselves.
+func (b bizError) Is(target error) bool {
+	t, ok := target.(*bizError)
+	return ok && b.defined && t.defined && b.code == t.code
 }
 
 func (b bizError) ValidationErrors() map[string]string {
@@ -99,12 +147,50 @@ func NewMultipleFieldErrors(fields map[string]string) BizError {
 	return NewValidationError("validation failed", fields)
 }
 
+// Codes of the standard errors
 var (
-	ErrBadRequest          = func(err error) BizError { return New(http.StatusBadRequest, err) }
-	ErrInternalServerError = func(err error) BizError {
-		return New(http.StatusInternalServerError, err)
-	}
-	ErrUnauthorized = func() BizError { return New(http.StatusUnauthorized, errors.New("please login you account")) }
-	ErrForbidden    = func() BizError { return New(http.StatusForbidden, errors.New("http forbidden")) }
-	ErrNotFound     = func() BizError { return New(http.StatusNotFound, errors.New("404 Not Found")) }
+	CodeBadRequest = Register(Definition{
+		Code: "bad_request", HTTPCode: http.StatusBadRequest,
+		MessageID: "error.bad_request", Message: "bad request",
+		Description: "The request is malformed",
+	})
+	CodeValidationFailed = Register(Definition{
+		Code: "validation_failed", HTTPCode: http.StatusBadRequest,
+		Message:     "validation failed",
+		Description: "Fields of the request are invalid, see the fields of the response",
+	})
+	CodeUnauthorized = Register(Definition{
+		Code: "unauthorized", HTTPCode: http.StatusUnauthorized,
+		MessageID: "error.unauthorized", Message: "please login you account",
+		Description: "Authentication is missing or invalid",
+	})
+	CodeForbidden = Register(Definition{
+		Code: "forbidden", HTTPCode: http.StatusForbidden,
+		MessageID: "error.forbidden", Message: "http forbidden",
+		Description: "The client is not allowed to perform the request",
+	})
+	CodeNotFound = Register(Definition{
+		Code: "not_found", HTTPCode: http.StatusNotFound,
+		MessageID: "error.not_found", Message: "404 Not Found",
+		Description: "The resource does not exist",
+	})
+	CodeRequestEntityTooLarge = Register(Definition{
+		Code: "request_entity_too_large", HTTPCode: http.StatusRequestEntityTooLarge,
+		MessageID: "error.request_entity_too_large", Message: "request entity too large",
+		Description: "The request body exceeds the size limit",
+	})
+	CodeInternalServerError = Register(Definition{
+		Code: "internal_server_error", HTTPCode: http.StatusInternalServerError,
+		MessageID: "error.internal_server", Message: "internal server error",
+		Description: "An unexpected error occurred on the server",
+	})
+)
+
+var (
+	ErrBadRequest            = func(err error) BizError { return CodeBadRequest.WithError(err) }
+	ErrInternalServerError   = func(err error) BizError { return wrap(err, CodeInternalServerError, true, "") }
+	ErrRequestEntityTooLarge = func(err error) BizError { return CodeRequestEntityTooLarge.WithError(err) }
+	ErrUnauthorized          = func() BizError { return CodeUnauthorized.New() }
+	ErrForbidden             = func() BizError { return CodeForbidden.New() }
+	ErrNotFound              = func() BizError { return CodeNotFound.New() }
 )
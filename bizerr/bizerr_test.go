@@ -0,0 +1,118 @@
+package bizerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var errRecordNotFound = errors.New("record not found")
+
+func TestWrap(t *testing.T) {
+	cause := fmt.Errorf("find user 42: %w", errRecordNotFound)
+
+	err := Wrap(cause, CodeNotFound.Code, "user not found")
+	if err.HTTPCode() != http.StatusNotFound || err.Code() != CodeNotFound.Code || err.Message() != "user not found" {
+		t.Fatalf("Wrap = %d %s %q", err.HTTPCode(), err.Code(), err.Message())
+	}
+	if err.Error() != "user not found: find user 42: record not found" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, errRecordNotFound) || errors.Unwrap(err) != cause {
+		t.Error("the cause is not in the chain")
+	}
+
+	catalogue := Wrap(cause, CodeNotFound.Code, "")
+	if catalogue.Message() != CodeNotFound.Message || catalogue.MessageID() != CodeNotFound.MessageID {
+		t.Errorf("empty message = %q (%s), want the catalogue message", catalogue.Message(), catalogue.MessageID())
+	}
+
+	unknown := Wrap(cause, "payment.declined", "")
+	if unknown.HTTPCode() != http.StatusInternalServerError || unknown.Message() != "internal server error" {
+		t.Errorf("unregistered code = %d %q", unknown.HTTPCode(), unknown.Message())
+	}
+
+	if Wrap(nil, CodeNotFound.Code, "") != nil {
+		t.Error("Wrap(nil) is not nil")
+	}
+}
+
+func TestIs(t *testing.T) {
+	wrapped := Wrap(errRecordNotFound, CodeNotFound.Code, "user not found")
+	if !errors.Is(wrapped, ErrNotFound()) || !errors.Is(ErrNotFound(), wrapped) {
+		t.Error("errors of the same registered code are not equal")
+	}
+	if errors.Is(wrapped, ErrForbidden()) {
+		t.Error("errors of different codes are equal")
+	}
+	if !errors.Is(NewSingleFieldError("email", "invalid"), NewMultipleFieldErrors(nil)) {
+		t.Error("validation errors are not equal")
+	}
+
+	// Errors that are not created from a Definition are only equal to themselves
+	adhoc := New(http.StatusBadRequest, errors.New("bad input"))
+	if errors.Is(adhoc, ErrBadRequest(errors.New("bad input"))) || errors.Is(ErrBadRequest(nil), adhoc) {
+		t.Error("an ad-hoc error is equal to a registered error with the same code")
+	}
+	if errors.Is(New(http.StatusInternalServerError, nil), New(http.StatusInternalServerError, nil)) {
+		t.Error("two ad-hoc errors are equal")
+	}
+	if !errors.Is(fmt.Errorf("handler: %w", adhoc), adhoc) {
+		t.Error("an ad-hoc error is not equal to itself")
+	}
+
+	var bizErr BizError
+	if !errors.As(fmt.Errorf("service: %w", wrapped), &bizErr) || bizErr.Code() != CodeNotFound.Code {
+		t.Error("errors.As does not find the BizError")
+	}
+}
+
+func TestServerErrorsHideTheirCause(t *testing.T) {
+	secret := errors.New("dial tcp 10.0.0.3:3306: connection refused")
+
+	tests := []struct {
+		name    string
+		err     BizError
+		message string
+	}{
+		{"New", New(http.StatusServiceUnavailable, secret), "service unavailable"},
+		{"ErrInternalServerError", ErrInternalServerError(secret), CodeInternalServerError.Message},
+		{"WithError", CodeInternalServerError.WithError(secret), CodeInternalServerError.Message},
+		{"Wrap", Wrap(secret, CodeInternalServerError.Code, ""), CodeInternalServerError.Message},
+	}
+	for _, tt := range tests {
+		if tt.err.Message() != tt.message {
+			t.Errorf("%s: Message() = %q, want %q", tt.name, tt.err.Message(), tt.message)
+		}
+		if !errors.Is(tt.err, secret) || !strings.Contains(tt.err.Error(), secret.Error()) {
+			t.Errorf("%s: the cause is not kept for logs: %q", tt.name, tt.err.Error())
+		}
+	}
+
+	if err := New(http.StatusBadRequest, errors.New("name is required")); err.Message() != "name is required" {
+		t.Errorf("client error message = %q", err.Message())
+	}
+}
+
+func TestStack(t *testing.T) {
+	err := fmt.Errorf("service: %w", Wrap(errRecordNotFound, CodeNotFound.Code, ""))
+
+	stack := Stack(err)
+	if !strings.HasPrefix(stack, "github.com/shyandsy/aurora/bizerr.TestStack") {
+		t.Errorf("stack does not start at the caller:\n%s", stack)
+	}
+	if Stack(errRecordNotFound) != "" {
+		t.Error("an error without BizError has a stack")
+	}
+
+	var bizErr BizError
+	errors.As(err, &bizErr)
+	if formatted := fmt.Sprintf("%+v", bizErr); !strings.Contains(formatted, "bizerr.TestStack") {
+		t.Errorf("%%+v does not print the stack:\n%s", formatted)
+	}
+	if formatted := fmt.Sprintf("%v", bizErr); formatted != bizErr.Error() {
+		t.Errorf("%%v = %q, want %q", formatted, bizErr.Error())
+	}
+}
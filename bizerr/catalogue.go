@@ -0,0 +1,153 @@
+package bizerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Definition describes an error code of the catalogue
+type Definition struct {
+	// Code is the stable, machine-readable code sent to clients, e.g. "user.not_found"
+	Code string `json:"code"`
+	// Number is an optional numeric code for clients that need one
+	Number   int `json:"number,omitempty"`
+	HTTPCode int `json:"http_status"`
+	// MessageID is the i18n message ID of Message; the default error handler
+	// translates it into the request language
+	MessageID string `json:"message_id,omitempty"`
+	// Message is the default message, sent when there is no translation
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+}
+
+var (
+	catalogueMu sync.RWMutex
+	catalogue   = make(map[string]Definition)
+)
+
+// Register adds def to the catalogue and returns it, so that error codes are
+// declared as package variables:
+//
+//	var ErrUserNotFound = bizerr.Register(bizerr.Definition{
+//		Code: "user.not_found", HTTPCode: http.StatusNotFound,
+//		MessageID: "error.user_not_found", Message: "user not found",
+//	})
+//
+// It panics when the code is empty or already registered.
+func Register(def Definition) Definition {
+	if def.Code == "" {
+		panic("bizerr: error code is required")
+	}
+	if def.HTTPCode == 0 {
+		def.HTTPCode = http.StatusInternalServerError
+	}
+
+	catalogueMu.Lock()
+	defer catalogueMu.Unlock()
+	if _, ok := catalogue[def.Code]; ok {
+		panic(fmt.Sprintf("bizerr: error code %q is already registered", def.Code))
+	}
+	catalogue[def.Code] = def
+	return def
+}
+
+// Lookup returns the definition of a registered code
+func Lookup(code string) (Definition, bool) {
+	catalogueMu.RLock()
+	defer catalogueMu.RUnlock()
+	def, ok := catalogue[code]
+	return def, ok
+}
+
+// Catalogue returns the registered definitions sorted by code
+func Catalogue() []Definition {
+	catalogueMu.RLock()
+	defs := make([]Definition, 0, len(catalogue))
+	for _, def := range catalogue {
+		defs = append(defs, def)
+	}
+	catalogueMu.RUnlock()
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Code < defs[j].Code
+	})
+	return defs
+}
+
+// CatalogueJSON exports the catalogue as a JSON array
+func CatalogueJSON() ([]byte, error) {
+	return json.MarshalIndent(Catalogue(), "", "  ")
+}
+
+// CatalogueMarkdown exports the catalogue as a markdown table
+func CatalogueMarkdown() string {
+	var b strings.Builder
+	b.WriteString("| Code | Number | HTTP status | Message ID | Message | Description |\n")
+	b.WriteString("|------|--------|-------------|------------|---------|-------------|\n")
+	for _, def := range Catalogue() {
+		number := ""
+		if def.Number != 0 {
+			number = strconv.Itoa(def.Number)
+		}
+		cells := []string{
+			"`" + def.Code + "`",
+			number,
+			fmt.Sprintf("%d %s", def.HTTPCode, http.StatusText(def.HTTPCode)),
+			markdownCell(def.MessageID),
+			markdownCell(def.Message),
+			markdownCell(def.Description),
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return b.String()
+}
+
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
+
+// New creates an error with the code, status and message of d
+func (d Definition) New() BizError {
+	return &bizError{
+		httpCode:  d.HTTPCode,
+		errorNo:   d.Number,
+		code:      d.Code,
+		messageID: d.MessageID,
+		message:   d.Message,
+		stack:     callers(0),
+		defined:   true,
+	}
+}
+
+// WithError creates an error with the code and status of d and the message
+// of err, which is not translated. Server errors (5xx) keep the message of d
+// and only log err.
+func (d Definition) WithError(err error) BizError {
+	if d.HTTPCode >= http.StatusInternalServerError {
+		return wrap(err, d, true, "")
+	}
+	return &bizError{
+		httpCode: d.HTTPCode,
+		errorNo:  d.Number,
+		code:     d.Code,
+		message:  errorMessage(err, d.HTTPCode),
+		cause:    err,
+		stack:    callers(0),
+		defined:  true,
+	}
+}
+
+// statusCode derives the code of errors created without a definition from
+// their HTTP status, e.g. "not_found" for 404
+func statusCode(httpCode int) string {
+	text := http.StatusText(httpCode)
+	if text == "" {
+		return "http_" + strconv.Itoa(httpCode)
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
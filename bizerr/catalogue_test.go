@@ -0,0 +1,105 @@
+package bizerr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var errCatalogueTest = Register(Definition{
+	Code: "catalogue_test.quota_exceeded", Number: 4291, HTTPCode: http.StatusTooManyRequests,
+	MessageID: "error.quota_exceeded", Message: "quota exceeded",
+	Description: "The monthly quota | of the plan is used up",
+})
+
+func TestRegister(t *testing.T) {
+	def, ok := Lookup(errCatalogueTest.Code)
+	if !ok || def != errCatalogueTest {
+		t.Fatalf("Lookup = %+v, %v", def, ok)
+	}
+	if _, ok := Lookup("catalogue_test.unknown"); ok {
+		t.Error("Lookup found an unregistered code")
+	}
+
+	if def := Register(Definition{Code: "catalogue_test.default_status"}); def.HTTPCode != http.StatusInternalServerError {
+		t.Errorf("default HTTPCode = %d, want 500", def.HTTPCode)
+	}
+
+	for name, def := range map[string]Definition{
+		"empty code":     {HTTPCode: http.StatusBadRequest},
+		"duplicate code": {Code: errCatalogueTest.Code, HTTPCode: http.StatusBadRequest},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: Register did not panic", name)
+				}
+			}()
+			Register(def)
+		}()
+	}
+}
+
+func TestBuiltinCodes(t *testing.T) {
+	for _, def := range []Definition{CodeBadRequest, CodeValidationFailed, CodeUnauthorized, CodeForbidden, CodeNotFound, CodeRequestEntityTooLarge, CodeInternalServerError} {
+		if registered, ok := Lookup(def.Code); !ok || registered != def {
+			t.Errorf("%s: registered = %+v, %v", def.Code, registered, ok)
+		}
+	}
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	err := errCatalogueTest.New()
+	if err.Code() != errCatalogueTest.Code || err.ErrorNo() != 4291 || err.HTTPCode() != http.StatusTooManyRequests ||
+		err.MessageID() != "error.quota_exceeded" || err.Message() != "quota exceeded" {
+		t.Errorf("New() = %+v", err)
+	}
+
+	// Client errors take the message of err, which is not translated
+	withErr := errCatalogueTest.WithError(errors.New("plan free allows 100 calls"))
+	if withErr.Code() != errCatalogueTest.Code || withErr.Message() != "plan free allows 100 calls" || withErr.MessageID() != "" {
+		t.Errorf("WithError() = %+v", withErr)
+	}
+	if !errors.Is(withErr, err) {
+		t.Error("errors of the same definition should match")
+	}
+}
+
+func TestCatalogueExport(t *testing.T) {
+	defs := Catalogue()
+	if !sort.SliceIsSorted(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code }) {
+		t.Error("Catalogue is not sorted by code")
+	}
+
+	data, err := CatalogueJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported []map[string]interface{}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != len(defs) {
+		t.Fatalf("JSON has %d definitions, want %d", len(exported), len(defs))
+	}
+	for _, entry := range exported {
+		if entry["code"] != errCatalogueTest.Code {
+			continue
+		}
+		if entry["number"] != float64(4291) || entry["http_status"] != float64(429) || entry["message_id"] != "error.quota_exceeded" {
+			t.Errorf("JSON entry = %v", entry)
+		}
+	}
+
+	markdown := CatalogueMarkdown()
+	row := "| `catalogue_test.quota_exceeded` | 4291 | 429 Too Many Requests | error.quota_exceeded | quota exceeded | The monthly quota \\| of the plan is used up |"
+	if !strings.Contains(markdown, row) {
+		t.Errorf("markdown does not contain %q:\n%s", row, markdown)
+	}
+	if lines := strings.Count(markdown, "\n"); lines != len(defs)+2 {
+		t.Errorf("markdown has %d lines, want %d", lines, len(defs)+2)
+	}
+}
@@ -0,0 +1,115 @@
+package bizerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Wrap creates an error with the status and number of the registered code
+// and the client message msg, keeping err as its cause. The cause is logged
+// but never sent to clients. An empty msg uses the (translated) message of
+// the code; an unregistered code gets a 500 status. Wrap returns nil for a nil err.
+func Wrap(err error, code, msg string) BizError {
+	if err == nil {
+		return nil
+	}
+	def, ok := Lookup(code)
+	if !ok {
+		def = Definition{Code: code, HTTPCode: http.StatusInternalServerError}
+	}
+	return wrap(err, def, ok, msg)
+}
+
+func wrap(err error, def Definition, defined bool, msg string) BizError {
+	b := &bizError{
+		httpCode: def.HTTPCode,
+		errorNo:  def.Number,
+		code:     def.Code,
+		message:  msg,
+		cause:    err,
+		// wrap is called by the exported constructors
+		stack:   callers(1),
+		defined: defined,
+	}
+	if msg == "" {
+		b.message = def.Message
+		b.messageID = def.MessageID
+	}
+	if b.message == "" {
+		b.message = errorMessage(nil, def.HTTPCode)
+	}
+	return b
+}
+
+// errorMessage is the message of err, or the status text without one
+func errorMessage(err error, httpCode int) string {
+	if err == nil {
+		return strings.ToLower(http.StatusText(httpCode))
+	}
+	return err.Error()
+}
+
+// clientMessage is the message of err, or the status text for server errors
+func clientMessage(err error, httpCode int) string {
+	if httpCode >= http.StatusInternalServerError {
+		return errorMessage(nil, httpCode)
+	}
+	return errorMessage(err, httpCode)
+}
+
+// callers records the stack of the creation of an error, from the caller of
+// the constructor that calls it, skipping skip more frames
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3+skip, pcs)
+	return pcs[:n]
+}
+
+// Stack returns the stack trace recorded when the innermost BizError of the
+// chain of err was created, or "" when there is none
+func Stack(err error) string {
+	var stack []uintptr
+	for err != nil {
+		if b, ok := err.(*bizError); ok {
+			stack = b.stack
+		}
+		err = errors.Unwrap(err)
+	}
+	return formatStack(stack)
+}
+
+func formatStack(stack []uintptr) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// Format prints the message and the cause with %v and %s, followed by the
+// stack trace with %+v
+func (b bizError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(s, b.Error())
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, "\n"+Stack(&b))
+		}
+	case 's':
+		_, _ = io.WriteString(s, b.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", b.Error())
+	}
+}
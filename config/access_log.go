@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Access log formats
+const (
+	AccessLogJSON   = "json"
+	AccessLogLogfmt = "logfmt"
+	// AccessLogText is a human readable line, the default of the local run level
+	AccessLogText = "text"
+)
+
+type AccessLogConfig struct {
+	Disabled bool   `env:"ACCESS_LOG_DISABLED,omitempty"`
+	Format   string `env:"ACCESS_LOG_FORMAT,omitempty"`
+	// SampleRate is the share of requests logged, from 0 to 1; nil when unset.
+	// Server errors and slow requests are always logged.
+	SampleRate *float64 `env:"ACCESS_LOG_SAMPLE_RATE,omitempty"`
+	// SkipPaths are not logged unless they fail with a server error or are slow
+	SkipPaths []string `env:"ACCESS_LOG_SKIP_PATHS,omitempty"`
+	// SlowThreshold flags slower requests as slow and logs them at warn level; zero disables it
+	SlowThreshold time.Duration `env:"ACCESS_LOG_SLOW_THRESHOLD,omitempty"`
+}
+
+func (s *AccessLogConfig) Key() string {
+	return "access_log"
+}
+
+func (s *AccessLogConfig) Validate() error {
+	switch s.Format {
+	case AccessLogJSON, AccessLogLogfmt, AccessLogText:
+	default:
+		return NewConfigError(fmt.Sprintf("ACCESS_LOG_FORMAT must be one of: %s, %s, %s", AccessLogJSON, AccessLogLogfmt, AccessLogText))
+	}
+
+	if s.SampleRate != nil && (*s.SampleRate < 0 || *s.SampleRate > 1) {
+		return NewConfigError("ACCESS_LOG_SAMPLE_RATE should be in [0, 1]")
+	}
+
+	if s.SlowThreshold < 0 {
+		return NewConfigError("ACCESS_LOG_SLOW_THRESHOLD should not be negative")
+	}
+
+	return nil
+}
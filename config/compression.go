@@ -0,0 +1,64 @@
+package config
+
+import "fmt"
+
+const (
+	CompressionGzip    = "gzip"
+	CompressionDeflate = "deflate"
+	CompressionBrotli  = "br"
+)
+
+// DefaultCompressionContentTypes are compressed when COMPRESSION_CONTENT_TYPES is not set.
+// A trailing "/*" matches every subtype.
+var DefaultCompressionContentTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/x-ndjson",
+	"application/xml",
+	"application/yaml",
+	"application/x-yaml",
+	"application/javascript",
+	"image/svg+xml",
+	"text/*",
+}
+
+type CompressionConfig struct {
+	Enabled bool `env:"COMPRESSION_ENABLED,omitempty"`
+	// Encodings lists the accepted encodings in order of preference
+	Encodings []string `env:"COMPRESSION_ENCODINGS,omitempty"`
+	// Level is the compression level from 1 (fastest) to 9 (smallest); zero uses the encoder default
+	Level int `env:"COMPRESSION_LEVEL,omitempty"`
+	// MinSize is the body size in bytes below which responses are sent uncompressed
+	MinSize      int      `env:"COMPRESSION_MIN_SIZE,omitempty"`
+	ContentTypes []string `env:"COMPRESSION_CONTENT_TYPES,omitempty"`
+}
+
+func (s *CompressionConfig) Key() string {
+	return "compression"
+}
+
+func (s *CompressionConfig) Validate() error {
+	if len(s.Encodings) == 0 {
+		return NewConfigError("COMPRESSION_ENCODINGS is required")
+	}
+
+	for _, encoding := range s.Encodings {
+		if encoding == "" || encoding == "identity" || encoding == "*" {
+			return NewConfigError(fmt.Sprintf("invalid compression encoding: %q", encoding))
+		}
+	}
+
+	if s.Level < 0 || s.Level > 9 {
+		return NewConfigError("COMPRESSION_LEVEL should be in [1, 9]")
+	}
+
+	if s.MinSize < 0 {
+		return NewConfigError("COMPRESSION_MIN_SIZE should not be negative")
+	}
+
+	if len(s.ContentTypes) == 0 {
+		return NewConfigError("COMPRESSION_CONTENT_TYPES is required")
+	}
+
+	return nil
+}
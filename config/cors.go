@@ -1,27 +1,73 @@
 package config
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
 type CORSConfig struct {
-	AllowedOrigins   []string `env:"CORS_ALLOWED_ORIGINS,omitempty"`
-	AllowedMethods   []string `env:"CORS_ALLOWED_METHODS,omitempty"`
-	AllowedHeaders   []string `env:"CORS_ALLOWED_HEADERS,omitempty"`
-	AllowCredentials bool     `env:"CORS_ALLOWED_CREDENTIALS,omitempty"`
+	// AllowedOrigins lists exact origins, "*", or origins with one wildcard such as https://*.example.com
+	AllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS,omitempty"`
+	// AllowedOriginPatterns are regular expressions matched against the whole origin
+	AllowedOriginPatterns []string      `env:"CORS_ALLOWED_ORIGIN_PATTERNS,omitempty"`
+	AllowedMethods        []string      `env:"CORS_ALLOWED_METHODS,omitempty"`
+	AllowedHeaders        []string      `env:"CORS_ALLOWED_HEADERS,omitempty"`
+	ExposedHeaders        []string      `env:"CORS_EXPOSED_HEADERS,omitempty"`
+	AllowCredentials      bool          `env:"CORS_ALLOWED_CREDENTIALS,omitempty"`
+	MaxAge                time.Duration `env:"CORS_MAX_AGE,omitempty"`
+	AllowPrivateNetwork   bool          `env:"CORS_ALLOW_PRIVATE_NETWORK,omitempty"`
 }
 
 func (s *CORSConfig) Key() string {
 	return "cors"
 }
 
+// Enabled reports whether any CORS setting is provided
+func (s *CORSConfig) Enabled() bool {
+	return len(s.AllowedOrigins) > 0 || len(s.AllowedOriginPatterns) > 0 ||
+		len(s.AllowedMethods) > 0 || len(s.AllowedHeaders) > 0 || len(s.ExposedHeaders) > 0
+}
+
 func (s *CORSConfig) Validate() error {
-	if len(s.AllowedOrigins) == 0 {
-		return NewConfigError("CORS_ALLOWED_ORIGINS is required")
+	if len(s.AllowedOrigins) == 0 && len(s.AllowedOriginPatterns) == 0 {
+		return NewConfigError("CORS_ALLOWED_ORIGINS or CORS_ALLOWED_ORIGIN_PATTERNS is required")
+	}
+
+	if err := ValidateCORSOrigins(s.AllowedOrigins, s.AllowedOriginPatterns, s.AllowCredentials); err != nil {
+		return NewConfigError(err.Error())
 	}
 
-	if len(s.AllowedMethods) == 0 {
-		return NewConfigError("CORS_ALLOWED_METHODS is required")
+	if s.MaxAge < 0 {
+		return NewConfigError("CORS_MAX_AGE should not be negative")
+	}
+
+	return nil
+}
+
+// ValidateCORSOrigins checks origins and patterns, and rejects the "*" origin
+// with credentials, which browsers refuse
+func ValidateCORSOrigins(origins, patterns []string, credentials bool) error {
+	for _, origin := range origins {
+		if origin == "*" {
+			if credentials {
+				return fmt.Errorf("the \"*\" origin cannot be combined with credentials")
+			}
+			continue
+		}
+		if strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("invalid CORS origin %q: only one * is allowed", origin)
+		}
+		if !strings.Contains(origin, "://") {
+			return fmt.Errorf("invalid CORS origin %q: a scheme is required", origin)
+		}
 	}
 
-	if len(s.AllowedHeaders) == 0 {
-		return NewConfigError("CORS_ALLOWED_HEADERS is required")
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid CORS origin pattern %q: %v", pattern, err)
+		}
 	}
 
 	return nil
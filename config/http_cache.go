@@ -0,0 +1,27 @@
+package config
+
+type HTTPCacheConfig struct {
+	// ETagDisabled turns off the automatic ETags of GET responses
+	ETagDisabled bool `env:"HTTP_CACHE_ETAG_DISABLED,omitempty"`
+	// KeyPrefix prefixes the Redis keys of the response cache
+	KeyPrefix string `env:"HTTP_CACHE_KEY_PREFIX,omitempty"`
+	// MaxBodySize is the size in bytes above which responses are streamed
+	// without ETag and not stored in Redis
+	MaxBodySize int `env:"HTTP_CACHE_MAX_BODY_SIZE,omitempty"`
+}
+
+func (s *HTTPCacheConfig) Key() string {
+	return "http_cache"
+}
+
+func (s *HTTPCacheConfig) Validate() error {
+	if s.KeyPrefix == "" {
+		return NewConfigError("HTTP_CACHE_KEY_PREFIX is required")
+	}
+
+	if s.MaxBodySize <= 0 {
+		return NewConfigError("HTTP_CACHE_MAX_BODY_SIZE should be positive")
+	}
+
+	return nil
+}
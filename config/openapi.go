@@ -0,0 +1,29 @@
+package config
+
+import "strings"
+
+type OpenAPIConfig struct {
+	Enabled       bool     `env:"OPENAPI_ENABLED,omitempty"`
+	Path          string   `env:"OPENAPI_PATH,omitempty"`
+	SwaggerUI     bool     `env:"OPENAPI_SWAGGER_UI,omitempty"`
+	SwaggerUIPath string   `env:"OPENAPI_SWAGGER_UI_PATH,omitempty"`
+	Title         string   `env:"OPENAPI_TITLE,omitempty"`
+	Description   string   `env:"OPENAPI_DESCRIPTION,omitempty"`
+	ServerURLs    []string `env:"OPENAPI_SERVER_URLS,omitempty"`
+}
+
+func (s *OpenAPIConfig) Key() string {
+	return "openapi"
+}
+
+func (s *OpenAPIConfig) Validate() error {
+	if !strings.HasPrefix(s.Path, "/") {
+		return NewConfigError("OPENAPI_PATH must start with /")
+	}
+
+	if s.SwaggerUI && !strings.HasPrefix(s.SwaggerUIPath, "/") {
+		return NewConfigError("OPENAPI_SWAGGER_UI_PATH must start with /")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import "fmt"
+
+const (
+	RateLimitStoreRedis  = "redis"
+	RateLimitStoreMemory = "memory"
+)
+
+type RateLimitConfig struct {
+	// Store selects the counter store. Empty uses Redis when the redis feature
+	// is added and memory otherwise.
+	Store     string `env:"RATE_LIMIT_STORE,omitempty"`
+	KeyPrefix string `env:"RATE_LIMIT_KEY_PREFIX,omitempty"`
+}
+
+func (s *RateLimitConfig) Key() string {
+	return "ratelimit"
+}
+
+func (s *RateLimitConfig) Validate() error {
+	if s.Store != "" && s.Store != RateLimitStoreRedis && s.Store != RateLimitStoreMemory {
+		return NewConfigError(fmt.Sprintf("RATE_LIMIT_STORE must be one of: %s, %s", RateLimitStoreRedis, RateLimitStoreMemory))
+	}
+
+	if s.KeyPrefix == "" {
+		return NewConfigError("RATE_LIMIT_KEY_PREFIX is required")
+	}
+
+	return nil
+}
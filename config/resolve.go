@@ -60,6 +60,14 @@ func setFieldValue(field reflect.Value, value string, fieldName string) error {
 	}
 
 	switch field.Kind() {
+	case reflect.Ptr:
+		// Pointer fields tell an unset variable (nil) from a zero value
+		elem := reflect.New(field.Type().Elem())
+		if err := setFieldValue(elem.Elem(), value, fieldName); err != nil {
+			return err
+		}
+		field.Set(elem)
+
 	case reflect.String:
 		field.SetString(value)
 
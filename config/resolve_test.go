@@ -122,6 +122,36 @@ func TestResolveConfig_Duration(t *testing.T) {
 	}
 }
 
+// TestResolveConfig_Pointer tests that pointer fields tell unset variables from zero values
+func TestResolveConfig_Pointer(t *testing.T) {
+	type TestConfig struct {
+		Rate    *float64 `env:"TEST_RATE,omitempty"`
+		Unset   *float64 `env:"TEST_RATE_UNSET,omitempty"`
+		Enabled *bool    `env:"TEST_ENABLED,omitempty"`
+	}
+
+	os.Setenv("TEST_RATE", "0")
+	os.Setenv("TEST_ENABLED", "false")
+	defer os.Unsetenv("TEST_RATE")
+	defer os.Unsetenv("TEST_ENABLED")
+
+	cfg := &TestConfig{}
+	err := ResolveConfig(cfg)
+	if err != nil {
+		t.Fatalf("ResolveConfig failed: %v", err)
+	}
+
+	if cfg.Rate == nil || *cfg.Rate != 0 {
+		t.Errorf("Rate = %v, want pointer to 0", cfg.Rate)
+	}
+	if cfg.Unset != nil {
+		t.Errorf("Unset = %v, want nil", *cfg.Unset)
+	}
+	if cfg.Enabled == nil || *cfg.Enabled {
+		t.Errorf("Enabled = %v, want pointer to false", cfg.Enabled)
+	}
+}
+
 // TestResolveConfig_Slice tests slice type
 func TestResolveConfig_Slice(t *testing.T) {
 	type TestConfig struct {
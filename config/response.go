@@ -0,0 +1,42 @@
+package config
+
+import "fmt"
+
+const (
+	ResponseFormatJSON     = "json"
+	ResponseFormatXML      = "xml"
+	ResponseFormatYAML     = "yaml"
+	ResponseFormatMsgPack  = "msgpack"
+	ResponseFormatProtobuf = "protobuf"
+)
+
+var ValidResponseFormats = map[string]bool{
+	ResponseFormatJSON:     true,
+	ResponseFormatXML:      true,
+	ResponseFormatYAML:     true,
+	ResponseFormatMsgPack:  true,
+	ResponseFormatProtobuf: true,
+}
+
+type ResponseConfig struct {
+	Formats  []string `env:"RESPONSE_FORMATS,omitempty"`
+	Envelope bool     `env:"RESPONSE_ENVELOPE,omitempty"`
+}
+
+func (s *ResponseConfig) Key() string {
+	return "response"
+}
+
+func (s *ResponseConfig) Validate() error {
+	if len(s.Formats) == 0 {
+		return NewConfigError("RESPONSE_FORMATS is required")
+	}
+
+	for _, format := range s.Formats {
+		if !ValidResponseFormats[format] {
+			return NewConfigError(fmt.Sprintf("unsupported response format: %s, supported formats: json, xml, yaml, msgpack, protobuf", format))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// SecurityHeaderOff disables a security header that is set by default
+const SecurityHeaderOff = "off"
+
+// SecurityConfig configures the security headers and the client IP handling.
+// Unset values are filled from the profile of RUN_LEVEL, see ApplyProfile.
+type SecurityConfig struct {
+	HeadersDisabled bool `env:"SECURITY_HEADERS_DISABLED,omitempty"`
+	// HSTSMaxAge is the Strict-Transport-Security max-age in seconds; zero omits the header
+	HSTSMaxAge            int    `env:"SECURITY_HSTS_MAX_AGE,omitempty"`
+	HSTSIncludeSubdomains bool   `env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS,omitempty"`
+	HSTSPreload           bool   `env:"SECURITY_HSTS_PRELOAD,omitempty"`
+	ContentSecurityPolicy string `env:"SECURITY_CSP,omitempty"`
+	FrameOptions          string `env:"SECURITY_FRAME_OPTIONS,omitempty"`
+	ContentTypeOptions    string `env:"SECURITY_CONTENT_TYPE_OPTIONS,omitempty"`
+	ReferrerPolicy        string `env:"SECURITY_REFERRER_POLICY,omitempty"`
+
+	// TrustedProxies are the IPs and CIDRs whose X-Forwarded-For and X-Real-IP
+	// headers are used to resolve the client IP
+	TrustedProxies []string `env:"TRUSTED_PROXIES,omitempty"`
+	// TrustedPlatform is a header set by the platform with the client IP, e.g. CF-Connecting-IP
+	TrustedPlatform string `env:"TRUSTED_PLATFORM,omitempty"`
+	// IPAllowList and IPDenyList restrict the client IPs served; the deny list wins
+	IPAllowList []string `env:"IP_ALLOW_LIST,omitempty"`
+	IPDenyList  []string `env:"IP_DENY_LIST,omitempty"`
+}
+
+func (s *SecurityConfig) Key() string {
+	return "security"
+}
+
+// ApplyProfile fills unset values with the defaults of runLevel. Production
+// sends HSTS for a year and trusts no proxy; local and stage omit HSTS and
+// trust loopback proxies only.
+func (s *SecurityConfig) ApplyProfile(runLevel string) {
+	if s.FrameOptions == "" {
+		s.FrameOptions = "DENY"
+	}
+	if s.ContentTypeOptions == "" {
+		s.ContentTypeOptions = "nosniff"
+	}
+	if s.ReferrerPolicy == "" {
+		s.ReferrerPolicy = "strict-origin-when-cross-origin"
+	}
+
+	if runLevel == RunLevelProduction {
+		if s.HSTSMaxAge == 0 {
+			s.HSTSMaxAge = 31536000
+		}
+		return
+	}
+
+	if len(s.TrustedProxies) == 0 {
+		s.TrustedProxies = []string{"127.0.0.1", "::1"}
+	}
+}
+
+func (s *SecurityConfig) Validate() error {
+	if s.HSTSMaxAge < 0 {
+		return NewConfigError("SECURITY_HSTS_MAX_AGE should not be negative")
+	}
+
+	if s.HSTSPreload && (s.HSTSMaxAge < 31536000 || !s.HSTSIncludeSubdomains) {
+		return NewConfigError("SECURITY_HSTS_PRELOAD requires SECURITY_HSTS_MAX_AGE of at least 31536000 and SECURITY_HSTS_INCLUDE_SUBDOMAINS")
+	}
+
+	switch strings.ToUpper(s.FrameOptions) {
+	case "DENY", "SAMEORIGIN", strings.ToUpper(SecurityHeaderOff):
+	default:
+		return NewConfigError("SECURITY_FRAME_OPTIONS must be one of: DENY, SAMEORIGIN, off")
+	}
+
+	if s.ContentTypeOptions != "nosniff" && s.ContentTypeOptions != SecurityHeaderOff {
+		return NewConfigError("SECURITY_CONTENT_TYPE_OPTIONS must be one of: nosniff, off")
+	}
+
+	for name, list := range map[string][]string{
+		"TRUSTED_PROXIES": s.TrustedProxies,
+		"IP_ALLOW_LIST":   s.IPAllowList,
+		"IP_DENY_LIST":    s.IPDenyList,
+	} {
+		if _, err := ParseIPNets(list); err != nil {
+			return NewConfigError(fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	return nil
+}
+
+// ParseIPNets parses IPs and CIDRs; a plain IP is a single address network
+func ParseIPNets(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", value)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", value)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"net"
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,33 @@ const (
 	RunLevelProduction = "production"
 )
 
+// TLS versions, cipher policies and client authentication modes
+const (
+	TLSVersion12 = "1.2"
+	TLSVersion13 = "1.3"
+
+	// TLSCipherPolicyDefault uses the Go defaults
+	TLSCipherPolicyDefault = "default"
+	// TLSCipherPolicyIntermediate limits TLS 1.2 to ECDHE key exchange with AEAD ciphers
+	TLSCipherPolicyIntermediate = "intermediate"
+	// TLSCipherPolicyModern accepts TLS 1.3 only
+	TLSCipherPolicyModern = "modern"
+
+	TLSClientAuthNone = "none"
+	// TLSClientAuthOptional verifies client certificates when presented
+	TLSClientAuthOptional = "optional"
+	// TLSClientAuthRequire rejects clients without a valid certificate
+	TLSClientAuthRequire = "require"
+)
+
+// Listener schemes accepted by LISTEN
+const (
+	ListenTCP     = "tcp"
+	ListenUnix    = "unix"
+	ListenFD      = "fd"
+	ListenSystemd = "systemd"
+)
+
 var ValidRunLevels = map[string]bool{
 	RunLevelLocal:      true,
 	RunLevelStage:      true,
@@ -29,6 +57,30 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `env:"WRITE_TIMEOUT" envDefault:"30s"`
 	IdleTimeout     time.Duration `env:"IDLE_TIMEOUT" envDefault:"60s"`
 	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"5s"`
+	// HandlerTimeout bounds the handling of each route without its own Timeout; zero disables it
+	HandlerTimeout time.Duration `env:"HANDLER_TIMEOUT,omitempty"`
+	// MaxBodySize limits request bodies in bytes; zero disables the limit
+	MaxBodySize int64 `env:"MAX_BODY_SIZE,omitempty"`
+	// StrictJSON rejects unknown fields and trailing data in JSON bodies decoded with DecodeJSON
+	StrictJSON bool `env:"STRICT_JSON,omitempty"`
+	// JSONMaxDepth limits the nesting of JSON bodies decoded with DecodeJSON; zero uses 32
+	JSONMaxDepth int `env:"JSON_MAX_DEPTH,omitempty"`
+	// Listen replaces HOST:PORT with a listener spec: tcp:ADDR, unix:PATH, fd:N or systemd[:NAME]
+	Listen string `env:"LISTEN,omitempty"`
+	// RestartTimeout bounds how long a graceful restart waits for the new process to serve
+	RestartTimeout time.Duration `env:"RESTART_TIMEOUT,omitempty"`
+
+	// TLS is enabled when the certificate and key files are set. The files are
+	// reloaded when they change, checked at most every TLS_RELOAD_INTERVAL.
+	TLSCertFile       string        `env:"TLS_CERT_FILE,omitempty"`
+	TLSKeyFile        string        `env:"TLS_KEY_FILE,omitempty"`
+	TLSMinVersion     string        `env:"TLS_MIN_VERSION,omitempty"`
+	TLSCipherPolicy   string        `env:"TLS_CIPHER_POLICY,omitempty"`
+	TLSReloadInterval time.Duration `env:"TLS_RELOAD_INTERVAL,omitempty"`
+	TLSClientCAFile   string        `env:"TLS_CLIENT_CA_FILE,omitempty"`
+	TLSClientAuth     string        `env:"TLS_CLIENT_AUTH,omitempty"`
+	// H2C serves cleartext HTTP/2, e.g. behind a proxy terminating TLS
+	H2C bool `env:"H2C_ENABLED,omitempty"`
 
 	Name     string `env:"SERVICE_NAME" envDefault:"myapp"`
 	Version  string `env:"SERVICE_VERSION" envDefault:"1.0.0"`
@@ -45,8 +97,41 @@ func (s *ServerConfig) Validate() error {
 		return NewConfigError("HOST should be a valid IP address")
 	}
 
-	if s.Port < 1 || s.Port > 65535 {
-		return NewConfigError("PORT should be in [1, 65535]")
+	if s.Port < 0 || s.Port > 65535 {
+		return NewConfigError("PORT should be in [0, 65535]")
+	}
+
+	if s.Listen != "" {
+		scheme, value, _ := strings.Cut(s.Listen, ":")
+		switch scheme {
+		case ListenTCP, ListenUnix, ListenFD:
+			if value == "" {
+				return NewConfigError(fmt.Sprintf("LISTEN %s requires an address", scheme))
+			}
+		case ListenSystemd:
+		default:
+			return NewConfigError(fmt.Sprintf("LISTEN must start with one of: %s, %s, %s, %s", ListenTCP, ListenUnix, ListenFD, ListenSystemd))
+		}
+	}
+
+	if s.HandlerTimeout < 0 {
+		return NewConfigError("HANDLER_TIMEOUT should not be negative")
+	}
+
+	if s.MaxBodySize < 0 {
+		return NewConfigError("MAX_BODY_SIZE should not be negative")
+	}
+
+	if s.JSONMaxDepth < 0 {
+		return NewConfigError("JSON_MAX_DEPTH should not be negative")
+	}
+
+	if s.RestartTimeout < 0 {
+		return NewConfigError("RESTART_TIMEOUT should not be negative")
+	}
+
+	if err := s.validateTLS(); err != nil {
+		return err
 	}
 
 	if s.Name == "" {
@@ -60,6 +145,53 @@ func (s *ServerConfig) Validate() error {
 	return nil
 }
 
+func (s *ServerConfig) validateTLS() error {
+	if (s.TLSCertFile == "") != (s.TLSKeyFile == "") {
+		return NewConfigError("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	switch s.TLSMinVersion {
+	case "", TLSVersion12, TLSVersion13:
+	default:
+		return NewConfigError(fmt.Sprintf("TLS_MIN_VERSION must be one of: %s, %s", TLSVersion12, TLSVersion13))
+	}
+
+	switch s.TLSCipherPolicy {
+	case "", TLSCipherPolicyDefault, TLSCipherPolicyIntermediate, TLSCipherPolicyModern:
+	default:
+		return NewConfigError(fmt.Sprintf("TLS_CIPHER_POLICY must be one of: %s, %s, %s", TLSCipherPolicyDefault, TLSCipherPolicyIntermediate, TLSCipherPolicyModern))
+	}
+
+	if s.TLSReloadInterval < 0 {
+		return NewConfigError("TLS_RELOAD_INTERVAL should not be negative")
+	}
+
+	switch s.TLSClientAuth {
+	case "", TLSClientAuthNone:
+	case TLSClientAuthOptional, TLSClientAuthRequire:
+		if s.TLSClientCAFile == "" {
+			return NewConfigError("TLS_CLIENT_AUTH requires TLS_CLIENT_CA_FILE")
+		}
+	default:
+		return NewConfigError(fmt.Sprintf("TLS_CLIENT_AUTH must be one of: %s, %s, %s", TLSClientAuthNone, TLSClientAuthOptional, TLSClientAuthRequire))
+	}
+
+	if !s.TLSEnabled() && (s.TLSClientCAFile != "" || s.TLSMinVersion != "" || s.TLSCipherPolicy != "") {
+		return NewConfigError("TLS options require TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	if s.TLSEnabled() && s.H2C {
+		return NewConfigError("H2C_ENABLED cannot be combined with TLS, which negotiates HTTP/2 itself")
+	}
+
+	return nil
+}
+
+// TLSEnabled reports whether the server serves HTTPS
+func (s *ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != ""
+}
+
 func (s *ServerConfig) IsProduction() bool {
 	return s.RunLevel == RunLevelProduction
 }
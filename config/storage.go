@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	StorageDriverLocal  = "local"
+	StorageDriverMemory = "memory"
+	StorageDriverS3     = "s3"
+)
+
+var ValidStorageDrivers = map[string]bool{
+	StorageDriverLocal:  true,
+	StorageDriverMemory: true,
+	StorageDriverS3:     true,
+}
+
+type StorageConfig struct {
+	Driver   string `env:"STORAGE_DRIVER,omitempty"`
+	LocalDir string `env:"STORAGE_LOCAL_DIR,omitempty"`
+
+	// Signed download URLs
+	SigningKey    string        `env:"STORAGE_SIGNING_KEY,omitempty"`
+	URLPath       string        `env:"STORAGE_URL_PATH,omitempty"`
+	URLExpiry     time.Duration `env:"STORAGE_URL_EXPIRY,omitempty"`
+	PublicBaseURL string        `env:"STORAGE_PUBLIC_BASE_URL,omitempty"`
+
+	// S3-compatible backend
+	S3Endpoint  string `env:"STORAGE_S3_ENDPOINT,omitempty"`
+	S3Region    string `env:"STORAGE_S3_REGION,omitempty"`
+	S3Bucket    string `env:"STORAGE_S3_BUCKET,omitempty"`
+	S3AccessKey string `env:"STORAGE_S3_ACCESS_KEY,omitempty"`
+	S3SecretKey string `env:"STORAGE_S3_SECRET_KEY,omitempty"`
+	S3PathStyle bool   `env:"STORAGE_S3_PATH_STYLE,omitempty"`
+}
+
+func (s *StorageConfig) Key() string {
+	return "storage"
+}
+
+func (s *StorageConfig) Validate() error {
+	if !ValidStorageDrivers[s.Driver] {
+		return NewConfigError(fmt.Sprintf("STORAGE_DRIVER must be one of: %s, %s, %s", StorageDriverLocal, StorageDriverMemory, StorageDriverS3))
+	}
+
+	if s.Driver == StorageDriverLocal && s.LocalDir == "" {
+		return NewConfigError("STORAGE_LOCAL_DIR is required for the local driver")
+	}
+
+	if s.Driver == StorageDriverS3 {
+		if s.S3Endpoint == "" {
+			return NewConfigError("STORAGE_S3_ENDPOINT is required for the s3 driver")
+		}
+		if s.S3Bucket == "" {
+			return NewConfigError("STORAGE_S3_BUCKET is required for the s3 driver")
+		}
+		if s.S3AccessKey == "" || s.S3SecretKey == "" {
+			return NewConfigError("STORAGE_S3_ACCESS_KEY and STORAGE_S3_SECRET_KEY are required for the s3 driver")
+		}
+	}
+
+	if !strings.HasPrefix(s.URLPath, "/") {
+		return NewConfigError("STORAGE_URL_PATH must start with /")
+	}
+
+	if s.URLExpiry <= 0 {
+		return NewConfigError("STORAGE_URL_EXPIRY must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import "strings"
+
+type TemplateConfig struct {
+	// Dir is the template directory, used when the feature is not given an fs.FS
+	Dir       string `env:"TEMPLATE_DIR,omitempty"`
+	Extension string `env:"TEMPLATE_EXTENSION,omitempty"`
+	// Layout is the default layout of the views, e.g. layouts/base
+	Layout string `env:"TEMPLATE_LAYOUT,omitempty"`
+	// Reload parses the templates again on every render; always on in the local run level
+	Reload bool `env:"TEMPLATE_RELOAD,omitempty"`
+}
+
+func (s *TemplateConfig) Key() string {
+	return "template"
+}
+
+func (s *TemplateConfig) Validate() error {
+	if s.Dir == "" {
+		return NewConfigError("TEMPLATE_DIR is required")
+	}
+
+	if !strings.HasPrefix(s.Extension, ".") {
+		return NewConfigError("TEMPLATE_EXTENSION must start with .")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import "fmt"
+
+const (
+	// APIVersionByPath selects the version from the path, e.g. /api/v2/users
+	APIVersionByPath = "path"
+	// APIVersionByHeader selects the version from a request header, e.g. API-Version: v2
+	APIVersionByHeader = "header"
+	// APIVersionByMediaType selects the version from the Accept header, e.g. application/vnd.myapp.v2+json
+	APIVersionByMediaType = "media_type"
+)
+
+type APIVersionConfig struct {
+	Strategy string `env:"API_VERSION_STRATEGY,omitempty"`
+	Header   string `env:"API_VERSION_HEADER,omitempty"`
+	// MediaType is the vendor media type the version is appended to, e.g. application/vnd.myapp
+	MediaType string `env:"API_VERSION_MEDIA_TYPE,omitempty"`
+	// Default is the version used when the request names none; empty uses the latest
+	Default string `env:"API_VERSION_DEFAULT,omitempty"`
+}
+
+func (s *APIVersionConfig) Key() string {
+	return "api_version"
+}
+
+func (s *APIVersionConfig) Validate() error {
+	switch s.Strategy {
+	case APIVersionByPath:
+	case APIVersionByHeader:
+		if s.Header == "" {
+			return NewConfigError("API_VERSION_HEADER is required")
+		}
+	case APIVersionByMediaType:
+		if s.MediaType == "" {
+			return NewConfigError("API_VERSION_MEDIA_TYPE is required with API_VERSION_STRATEGY=media_type")
+		}
+	default:
+		return NewConfigError(fmt.Sprintf("API_VERSION_STRATEGY must be one of: %s, %s, %s", APIVersionByPath, APIVersionByHeader, APIVersionByMediaType))
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+type WebSocketConfig struct {
+	RedisChannel   string        `env:"WEBSOCKET_REDIS_CHANNEL,omitempty"`
+	PingInterval   time.Duration `env:"WEBSOCKET_PING_INTERVAL,omitempty"`
+	WriteTimeout   time.Duration `env:"WEBSOCKET_WRITE_TIMEOUT,omitempty"`
+	MaxMessageSize int64         `env:"WEBSOCKET_MAX_MESSAGE_SIZE,omitempty"`
+	AllowedOrigins []string      `env:"WEBSOCKET_ALLOWED_ORIGINS,omitempty"`
+}
+
+func (s *WebSocketConfig) Key() string {
+	return "websocket"
+}
+
+func (s *WebSocketConfig) Validate() error {
+	if s.PingInterval <= 0 {
+		return NewConfigError("WEBSOCKET_PING_INTERVAL must be positive")
+	}
+
+	if s.WriteTimeout < 0 {
+		return NewConfigError("WEBSOCKET_WRITE_TIMEOUT must not be negative")
+	}
+
+	if s.MaxMessageSize < 0 {
+		return NewConfigError("WEBSOCKET_MAX_MESSAGE_SIZE must not be negative")
+	}
+
+	return nil
+}
@@ -7,7 +7,14 @@ import (
 type App interface {
 	AddFeature(feature Features)
 	RegisterRoutes(routes []Route)
+	RegisterWebSocketRoutes(routes []WebSocketRoute)
+	RegisterStaticSites(sites []StaticSite)
 	Run() error
+	// RunCommand runs the command named by args[0] instead of the server:
+	// "openapi [file]" writes the OpenAPI document of the registered routes,
+	// "errors [json|markdown] [file]" writes the bizerr error catalogue.
+	// It reports false when args does not name a command.
+	RunCommand(args []string) (bool, error)
 	Shutdown() error
 
 	Name() string
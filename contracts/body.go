@@ -0,0 +1,176 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/shyandsy/aurora/bizerr"
+)
+
+const (
+	// ContextKeyStrictJSON is the gin.Context key enabling strict decoding in DecodeJSON
+	ContextKeyStrictJSON = "strict_json"
+	// ContextKeyJSONMaxDepth is the gin.Context key of the nesting limit of DecodeJSON
+	ContextKeyJSONMaxDepth = "json_max_depth"
+
+	// DefaultJSONMaxDepth is the nesting limit of DecodeJSON when JSON_MAX_DEPTH is not set
+	DefaultJSONMaxDepth = 32
+)
+
+// BodyError converts an error reading the request body into a bizerr: 413 when
+// the body exceeds the size limit of the route, 400 otherwise
+func BodyError(err error) bizerr.BizError {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return bizerr.ErrRequestEntityTooLarge(fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+	}
+	return bizerr.ErrBadRequest(fmt.Errorf("failed to read request body: %w", err))
+}
+
+// DecodeJSON decodes the JSON request body into obj and validates it with its
+// `binding` tags. Errors are bizerr values: 413 for a body over the size limit,
+// 400 with the offending fields for type and validation errors. Bodies nested
+// deeper than JSON_MAX_DEPTH are rejected. In strict mode (STRICT_JSON or
+// Route.StrictJSON), unknown fields and data after the JSON value are rejected.
+func (c *RequestContext) DecodeJSON(obj interface{}) bizerr.BizError {
+	if c.Request.Body == nil {
+		return bizerr.ErrBadRequest(errors.New("request body is empty"))
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return BodyError(err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return bizerr.ErrBadRequest(errors.New("request body is empty"))
+	}
+
+	maxDepth := c.GetInt(ContextKeyJSONMaxDepth)
+	if maxDepth <= 0 {
+		maxDepth = DefaultJSONMaxDepth
+	}
+	if err := checkJSONDepth(data, maxDepth); err != nil {
+		return bizerr.ErrBadRequest(err)
+	}
+
+	strict := c.GetBool(ContextKeyStrictJSON)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(obj); err != nil {
+		return jsonDecodeError(err)
+	}
+	if strict {
+		if _, err := decoder.Token(); err != io.EOF {
+			return bizerr.ErrBadRequest(errors.New("unexpected data after the JSON body"))
+		}
+	}
+
+	if binding.Validator == nil {
+		return nil
+	}
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return validationError(obj, err)
+	}
+	return nil
+}
+
+// checkJSONDepth rejects data whose objects and arrays are nested deeper than maxDepth
+func checkJSONDepth(data []byte, maxDepth int) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// Syntax errors are reported by the decoding
+			return nil
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("JSON body is nested deeper than %d levels", maxDepth)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+func jsonDecodeError(err error) bizerr.BizError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return bizerr.NewSingleFieldError(typeErr.Field, fmt.Sprintf("must be of type %s", typeErr.Type))
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return bizerr.NewSingleFieldError(strings.Trim(field, `"`), "unknown field")
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return bizerr.ErrBadRequest(errors.New("invalid JSON body: unexpected end of input"))
+	}
+	return bizerr.ErrBadRequest(fmt.Errorf("invalid JSON body: %w", err))
+}
+
+// validationError maps validator errors to the JSON paths of the fields of obj
+func validationError(obj interface{}, err error) bizerr.BizError {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return bizerr.ErrBadRequest(err)
+	}
+
+	fields := make(map[string]string, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+		fields[jsonFieldPath(reflect.TypeOf(obj), fieldErr.StructNamespace())] = "failed on the '" + rule + "' rule"
+	}
+	return bizerr.NewMultipleFieldErrors(fields)
+}
+
+// jsonFieldPath converts a validator namespace such as "Req.Items[0].Name"
+// into the JSON path of the field, e.g. "items[0].name"
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		jsonName := name
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(name); ok {
+				if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+					jsonName = tag
+				}
+				t = field.Type
+			} else {
+				t = nil
+			}
+		}
+		path = append(path, jsonName+index)
+	}
+	return strings.Join(path, ".")
+}
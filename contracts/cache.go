@@ -0,0 +1,75 @@
+package contracts
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContextKeyCacheTags is the gin.Context key of the tags added with CacheTags
+const ContextKeyCacheTags = "cache_tags"
+
+// CachePolicy declares how the responses of a GET route may be cached
+type CachePolicy struct {
+	// MaxAge is sent as Cache-Control max-age; zero sends "no-cache", which
+	// lets clients keep the response but revalidate it with its ETag
+	MaxAge time.Duration
+	// Private marks the response as specific to the user, so that proxies do not store it
+	Private bool
+	// NoStore forbids clients and proxies to store the response
+	NoStore bool
+	// StaleWhileRevalidate lets caches serve a stale response while they revalidate it
+	StaleWhileRevalidate time.Duration
+
+	// SharedTTL stores successful responses in the Redis response cache for
+	// that long; zero disables it. Entries are keyed by route, path and query
+	// parameters, user and the headers listed in Vary.
+	SharedTTL time.Duration
+	// Tags are the invalidation tags of the cached responses, see ResponseCache
+	Tags []string
+	// Vary lists the request headers the response depends on. Accept and
+	// Accept-Language are always included.
+	Vary []string
+}
+
+// CacheControl returns the Cache-Control header value of the policy
+func (p *CachePolicy) CacheControl() string {
+	if p.NoStore {
+		return "no-store"
+	}
+
+	directives := []string{"public"}
+	if p.Private {
+		directives[0] = "private"
+	}
+	if p.MaxAge > 0 {
+		directives = append(directives, "max-age="+strconv.Itoa(int(p.MaxAge/time.Second)))
+	} else {
+		directives = append(directives, "no-cache")
+	}
+	if p.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+strconv.Itoa(int(p.StaleWhileRevalidate/time.Second)))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// ResponseCache manages the Redis response cache of routes with CachePolicy.SharedTTL
+type ResponseCache interface {
+	// Invalidate removes the cached responses carrying any of tags
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// CacheTags adds invalidation tags to the cached response of this request,
+// e.g. "customer:42" for a response built from that customer.
+func (c *RequestContext) CacheTags(tags ...string) {
+	existing, _ := c.Get(ContextKeyCacheTags)
+	current, _ := existing.([]string)
+	c.Set(ContextKeyCacheTags, append(current, tags...))
+}
+
+// SetLastModified sets the Last-Modified header used to answer If-Modified-Since with 304
+func (c *RequestContext) SetLastModified(t time.Time) {
+	c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,37 @@
+package contracts
+
+import "time"
+
+// CORSPolicy is the CORS configuration of a set of routes, overriding the
+// CORS_* configuration for them, see WithCORS
+type CORSPolicy struct {
+	// AllowOrigins lists exact origins, "*", or origins with one wildcard such as https://*.example.com
+	AllowOrigins []string
+	// AllowOriginPatterns are regular expressions matched against the whole origin
+	AllowOriginPatterns []string
+	// AllowOriginFunc decides on origins not matched by AllowOrigins and AllowOriginPatterns
+	AllowOriginFunc func(origin string) bool
+
+	// AllowMethods and AllowHeaders default to the common methods and headers when empty
+	AllowMethods  []string
+	AllowHeaders  []string
+	ExposeHeaders []string
+	// AllowCredentials cannot be combined with the "*" origin
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight results
+	MaxAge time.Duration
+	// AllowPrivateNetwork answers Private Network Access preflights
+	AllowPrivateNetwork bool
+}
+
+// WithCORS applies policy to routes, e.g. to a group built with Group:
+//
+//	contracts.WithCORS(partnerCORS, contracts.Group("/partner", nil, routes...)...)
+func WithCORS(policy *CORSPolicy, routes ...Route) []Route {
+	result := make([]Route, 0, len(routes))
+	for _, r := range routes {
+		r.CORS = policy
+		result = append(result, r)
+	}
+	return result
+}
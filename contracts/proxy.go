@@ -0,0 +1,59 @@
+package contracts
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Proxy forwards the requests of a route to an upstream HTTP service. The
+// route middlewares (authentication, rate limits, ...) run before the request
+// is forwarded.
+type Proxy struct {
+	// Upstream is the base URL of the upstream, e.g. http://legacy:8080/api
+	Upstream string
+	// StripPrefix is removed from the request path, which is then appended to the upstream path
+	StripPrefix string
+	// Rewrite rewrites the path after StripPrefix, e.g. to rename resources
+	Rewrite func(path string) string
+	// PreserveHost sends the Host header of the client instead of the upstream host
+	PreserveHost bool
+
+	// SetHeaders and RemoveHeaders change the headers of the upstream request
+	SetHeaders    map[string]string
+	RemoveHeaders []string
+	// SetResponseHeaders and RemoveResponseHeaders change the headers of the upstream response
+	SetResponseHeaders    map[string]string
+	RemoveResponseHeaders []string
+
+	// Timeout bounds the wait for the response headers of each attempt; zero uses 30s
+	Timeout time.Duration
+	// Retries is the number of additional attempts for idempotent methods
+	// (GET, HEAD, OPTIONS, PUT, DELETE) after a connection error or a 502, 503 or 504
+	Retries int
+	// RetryBackoff is the delay before the first retry, growing linearly; zero uses 100ms
+	RetryBackoff time.Duration
+	// RetryMaxBodySize bounds the request body buffered to replay it on
+	// retries; larger bodies are streamed and not retried. Zero uses 1MiB.
+	RetryMaxBodySize int64
+}
+
+// ProxyRoutes forwards GET, HEAD, POST, PUT, PATCH and DELETE requests on path
+// to proxy. Use a wildcard to forward a whole tree, e.g. "/legacy/*path".
+func ProxyRoutes(path string, proxy *Proxy, middlewares ...gin.HandlerFunc) []Route {
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost,
+		http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}
+	routes := make([]Route, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, Route{
+			Method:      method,
+			Path:        path,
+			Proxy:       proxy,
+			Middlewares: middlewares,
+		})
+	}
+	return routes
+}
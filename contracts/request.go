@@ -1,17 +1,40 @@
 package contracts
 
 import (
+	"crypto/x509"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/logger"
 )
 
+// RequestIDHeader carries the request ID on requests and responses
+const RequestIDHeader = "X-Request-ID"
+
 type RequestContext struct {
 	*gin.Context
 	App        App
 	Translator Translator
 }
 
+// RequestID returns the ID of the current request. It is also carried by
+// c.Request.Context(), so pass that context to the database, Redis, mail and
+// HTTP clients to correlate their logs and calls with the request.
+func (c *RequestContext) RequestID() string {
+	return logger.RequestID(c.Request.Context())
+}
+
+// ClientCertificate returns the client certificate verified by mutual TLS,
+// or nil when the client did not present one (see TLS_CLIENT_AUTH).
+func (c *RequestContext) ClientCertificate() *x509.Certificate {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return c.Request.TLS.VerifiedChains[0][0]
+}
+
 func (c *RequestContext) GetLang() string {
 	if lang := c.Query("lang"); lang != "" {
 		return lang
@@ -41,3 +64,15 @@ func (c *RequestContext) T(id string, data ...interface{}) string {
 	lang := c.GetLang()
 	return c.Translator.TWithLang(lang, id, data...)
 }
+
+// ErrorMessage returns the message of err sent to the client, translated into
+// the request language when err comes from a catalogue definition with a
+// MessageID and the Translator knows it
+func (c *RequestContext) ErrorMessage(err bizerr.BizError) string {
+	if id := err.MessageID(); id != "" {
+		if message := c.T(id); message != id {
+			return message
+		}
+	}
+	return err.Message()
+}
@@ -0,0 +1,57 @@
+package contracts
+
+import "net/http"
+
+// Response lets a handler control the status code, headers and cookies of a
+// successful response. Return it (or a pointer to it) as the handler result;
+// Body is rendered in the negotiated format. A nil Body writes no content.
+type Response struct {
+	Status  int
+	Headers http.Header
+	Cookies []*http.Cookie
+	Body    interface{}
+}
+
+// NewResponse creates a response with the given status code and body.
+func NewResponse(status int, body interface{}) *Response {
+	return &Response{Status: status, Body: body}
+}
+
+// OK creates a 200 response.
+func OK(body interface{}) *Response {
+	return NewResponse(http.StatusOK, body)
+}
+
+// Created creates a 201 response with a Location header.
+func Created(location string, body interface{}) *Response {
+	return NewResponse(http.StatusCreated, body).WithHeader("Location", location)
+}
+
+// Accepted creates a 202 response.
+func Accepted(body interface{}) *Response {
+	return NewResponse(http.StatusAccepted, body)
+}
+
+// NoContent creates a 204 response.
+func NoContent() *Response {
+	return NewResponse(http.StatusNoContent, nil)
+}
+
+// WithHeader sets a response header.
+func (r *Response) WithHeader(key, value string) *Response {
+	if r.Headers == nil {
+		r.Headers = make(http.Header)
+	}
+	r.Headers.Set(key, value)
+	return r
+}
+
+// WithCookie adds a Set-Cookie header.
+func (r *Response) WithCookie(cookie *http.Cookie) *Response {
+	r.Cookies = append(r.Cookies, cookie)
+	return r
+}
+
+// EnvelopeFunc wraps the body of every successful response, e.g. into
+// {"code": 0, "data": ..., "message": "success"}.
+type EnvelopeFunc func(c *RequestContext, status int, data interface{}) interface{}
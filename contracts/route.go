@@ -1,6 +1,9 @@
 package contracts
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/shyandsy/aurora/bizerr"
 )
@@ -12,4 +15,65 @@ type Route struct {
 	Path        string
 	Handler     CustomizedHandlerFunc
 	Middlewares []gin.HandlerFunc
+	// Proxy forwards the route to an upstream instead of calling Handler, see ProxyRoutes
+	Proxy *Proxy
+
+	// Timeout bounds the middlewares and handler of the route. Its deadline is
+	// set on the request context, so database and Redis calls made with it are
+	// cancelled. Zero uses HANDLER_TIMEOUT, a negative value disables it
+	// (e.g. for streaming routes).
+	Timeout time.Duration
+
+	// MaxBodySize limits the request body in bytes; larger bodies get a 413.
+	// Zero uses MAX_BODY_SIZE, a negative value disables the limit.
+	MaxBodySize int64
+	// StrictJSON makes DecodeJSON reject unknown fields and trailing data, as STRICT_JSON does for all routes
+	StrictJSON bool
+
+	// Version and VersionBase are set by Versions: the route path is
+	// VersionBase + "/" + Version + the path given to the version.
+	Version     string
+	VersionBase string
+	// Deprecation marks the route as deprecated, see Deprecation
+	Deprecation *Deprecation
+
+	// CORS overrides the CORS configuration for the route, see WithCORS
+	CORS *CORSPolicy
+
+	// Cache declares the Cache-Control header of a GET route and optionally
+	// stores its responses in Redis, see CachePolicy
+	Cache *CachePolicy
+
+	// Optional API documentation, used to build the OpenAPI document.
+	// Request and Response take a value (or pointer) of the typed struct,
+	// e.g. Request: dto.CreateCustomerReq{}, Response: dto.Customer{}.
+	// Status is the success status of the handler, e.g. http.StatusCreated;
+	// zero documents 200. Secured documents that the route requires a bearer
+	// token, e.g. because its middlewares include JWTAuthMiddleware.
+	Summary     string
+	Description string
+	Tags        []string
+	Request     interface{}
+	Response    interface{}
+	Status      int
+	Secured     bool
+}
+
+// Group prefixes the path of routes with prefix and runs middlewares before
+// their own, to apply a policy (authentication, rate limits, ...) to a set of routes.
+func Group(prefix string, middlewares []gin.HandlerFunc, routes ...Route) []Route {
+	grouped := make([]Route, 0, len(routes))
+	for _, r := range routes {
+		r.Path = strings.TrimSuffix(prefix, "/") + r.Path
+		r.Middlewares = append(append([]gin.HandlerFunc{}, middlewares...), r.Middlewares...)
+		grouped = append(grouped, r)
+	}
+	return grouped
+}
+
+// AbortWithError stops the middleware chain and lets the server render err
+// with the configured ErrorHandler, like an error returned by a handler.
+func AbortWithError(c *gin.Context, err bizerr.BizError) {
+	_ = c.Error(err)
+	c.Abort()
 }
@@ -1,6 +1,10 @@
 package contracts
 
-import "github.com/gin-gonic/gin"
+import (
+	"net"
+
+	"github.com/gin-gonic/gin"
+)
 
 // ErrorHandler handles HTTP error responses. Implement this interface to customize error handling.
 type ErrorHandler interface {
@@ -10,6 +14,12 @@ type ErrorHandler interface {
 type ServerFeature interface {
 	Features
 	RegisterRoutes(routes []Route)
+	RegisterWebSocketRoutes(routes []WebSocketRoute)
+	RegisterStaticSites(sites []StaticSite)
 	Start() error
 	Wait()
+	// Addr returns the address the server is bound to, or nil before Start.
+	Addr() net.Addr
+	// OpenAPI returns the OpenAPI document of the registered routes as JSON.
+	OpenAPI() ([]byte, error)
 }
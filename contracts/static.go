@@ -0,0 +1,30 @@
+package contracts
+
+import (
+	"io/fs"
+	"time"
+)
+
+// StaticSite serves the files of FS under Prefix, e.g. an admin front-end
+// embedded with go:embed or a build directory opened with os.DirFS.
+type StaticSite struct {
+	// Prefix is the URL path the site is mounted on, e.g. "/admin" or "/"
+	Prefix string
+	// FS holds the files of the site; use fs.Sub to serve a subdirectory of an embed.FS
+	FS fs.FS
+	// Index is the file served for directories and by the SPA fallback; empty uses index.html
+	Index string
+
+	// SPA serves Index for unknown paths without a file extension requested by
+	// browsers (Accept: text/html), so that client-side routes can be reloaded
+	SPA bool
+	// Exclude lists path prefixes under Prefix that never fall back to Index, e.g. "/admin/api"
+	Exclude []string
+
+	// MaxAge is the browser cache lifetime of the files; zero sends "no-cache",
+	// so that browsers revalidate them with their ETag
+	MaxAge time.Duration
+	// Immutable lists path.Match patterns, relative to Prefix, of fingerprinted
+	// files cached for a year, e.g. "assets/*"
+	Immutable []string
+}
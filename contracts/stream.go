@@ -0,0 +1,91 @@
+package contracts
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// SSEEvent is a single Server-Sent Event. Data is sent as is when it is a
+// string and JSON-encoded otherwise.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  interface{}
+	Retry time.Duration
+}
+
+// SSEWriter sends events to the client of an EventStream.
+type SSEWriter interface {
+	Send(event SSEEvent) error
+	// LastEventID returns the Last-Event-ID header sent by a reconnecting client.
+	LastEventID() string
+	// Context is cancelled when the client disconnects or the server shuts down.
+	Context() context.Context
+}
+
+// EventStream is a handler result that streams Server-Sent Events.
+// Heartbeat sends a comment line at the given interval to keep proxies from
+// closing idle connections. Retry is sent once as the client reconnect delay.
+type EventStream struct {
+	Heartbeat time.Duration
+	Retry     time.Duration
+	Handler   func(w SSEWriter) error
+}
+
+// NewEventStream creates an EventStream that runs fn until it returns.
+func NewEventStream(fn func(w SSEWriter) error) *EventStream {
+	return &EventStream{Handler: fn}
+}
+
+// WithHeartbeat sets the heartbeat interval.
+func (s *EventStream) WithHeartbeat(interval time.Duration) *EventStream {
+	s.Heartbeat = interval
+	return s
+}
+
+// WithRetry sets the reconnect delay sent to the client.
+func (s *EventStream) WithRetry(retry time.Duration) *EventStream {
+	s.Retry = retry
+	return s
+}
+
+// StreamWriter writes records of a chunked NDJSON stream, one JSON value per line.
+type StreamWriter interface {
+	Write(v interface{}) error
+	// Context is cancelled when the client disconnects or the server shuts down.
+	Context() context.Context
+}
+
+// NDJSONStream is a handler result that streams newline-delimited JSON.
+type NDJSONStream struct {
+	Handler func(w StreamWriter) error
+}
+
+// NewNDJSONStream creates an NDJSONStream that runs fn until it returns.
+func NewNDJSONStream(fn func(w StreamWriter) error) *NDJSONStream {
+	return &NDJSONStream{Handler: fn}
+}
+
+// FileResponse is a handler result that sends a file. Either Path or Reader is
+// set. Readers implementing io.ReadSeeker support Range requests; readers
+// implementing io.Closer are closed once sent.
+type FileResponse struct {
+	Path        string
+	Reader      io.Reader
+	Name        string
+	ContentType string
+	ModTime     time.Time
+	// Inline displays the file in the browser instead of downloading it.
+	Inline bool
+}
+
+// FileAttachment sends the file at path as a download named name.
+func FileAttachment(path, name string) *FileResponse {
+	return &FileResponse{Path: path, Name: name}
+}
+
+// FileFromReader sends the content of r as a download named name.
+func FileFromReader(r io.Reader, name, contentType string) *FileResponse {
+	return &FileResponse{Reader: r, Name: name, ContentType: contentType}
+}
@@ -0,0 +1,81 @@
+package contracts
+
+import (
+	"strings"
+	"time"
+)
+
+// ContextKeyAPIVersion is the gin.Context key of the API version of the matched route
+const ContextKeyAPIVersion = "api_version"
+
+// Deprecation marks a route or an API version as deprecated. Responses carry
+// the Deprecation header, and Sunset and Link headers when set.
+type Deprecation struct {
+	// Since is the deprecation date; zero sends "Deprecation: true"
+	Since time.Time
+	// Sunset is the date after which the route may stop working
+	Sunset time.Time
+	// Link points to migration documentation
+	Link string
+}
+
+// APIVersion is a named set of routes, e.g. "v1"
+type APIVersion struct {
+	Name string
+	// Deprecation applies to the routes defined or inherited by this version
+	// that are not deprecated themselves
+	Deprecation *Deprecation
+	Routes      []Route
+}
+
+// Versions registers each version under base + "/" + Name. A version inherits
+// the routes of the previous one that it does not redefine with the same
+// Method and Path; a route without Handler and Proxy removes an inherited route.
+// It panics when base is empty or "/", since the header and media type
+// strategies would then route every path of the server to a version.
+func Versions(base string, versions ...APIVersion) []Route {
+	base = strings.TrimSuffix(base, "/")
+	if base == "" {
+		panic("contracts: Versions requires a base path, e.g. /api")
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	current := make(map[string]Route)
+	var routes []Route
+
+	for _, version := range versions {
+		for _, r := range version.Routes {
+			key := r.Method + " " + r.Path
+			if r.Handler == nil && r.Proxy == nil {
+				delete(current, key)
+				continue
+			}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+			current[key] = r
+		}
+
+		for _, key := range keys {
+			r, ok := current[key]
+			if !ok {
+				continue
+			}
+			r.Path = base + "/" + version.Name + r.Path
+			r.Version = version.Name
+			r.VersionBase = base
+			if r.Deprecation == nil {
+				r.Deprecation = version.Deprecation
+			}
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
+// APIVersion returns the API version of the matched route, set for routes registered with Versions
+func (c *RequestContext) APIVersion() string {
+	return c.GetString(ContextKeyAPIVersion)
+}
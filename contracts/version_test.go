@@ -0,0 +1,63 @@
+package contracts
+
+import (
+	"testing"
+
+	"github.com/shyandsy/aurora/bizerr"
+)
+
+func TestVersions(t *testing.T) {
+	handler := func(c *RequestContext) (interface{}, bizerr.BizError) { return nil, nil }
+	proxy := &Proxy{Upstream: "http://billing:8080"}
+	deprecated := &Deprecation{Link: "https://example.com/migrate"}
+
+	routes := Versions("/api/",
+		APIVersion{Name: "v1", Deprecation: deprecated, Routes: append([]Route{
+			{Method: "GET", Path: "/users", Handler: handler},
+			{Method: "GET", Path: "/legacy", Handler: handler},
+		}, ProxyRoutes("/billing/*path", proxy)...)},
+		APIVersion{Name: "v2", Routes: []Route{
+			{Method: "GET", Path: "/legacy"},
+			{Method: "POST", Path: "/users", Handler: handler},
+		}},
+	)
+
+	got := make(map[string]Route)
+	for _, r := range routes {
+		got[r.Method+" "+r.Path] = r
+	}
+	for _, key := range []string{
+		"GET /api/v1/users", "GET /api/v1/legacy", "GET /api/v1/billing/*path", "DELETE /api/v1/billing/*path",
+		"GET /api/v2/users", "POST /api/v2/users", "GET /api/v2/billing/*path", "DELETE /api/v2/billing/*path",
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("route %s is missing", key)
+		}
+	}
+	if _, ok := got["GET /api/v2/legacy"]; ok {
+		t.Error("route GET /api/v2/legacy was not removed")
+	}
+	if len(routes) != 2+6+2+6 {
+		t.Errorf("len(routes) = %d, want 16", len(routes))
+	}
+
+	if r := got["GET /api/v2/billing/*path"]; r.Proxy != proxy || r.Version != "v2" || r.VersionBase != "/api" || r.Deprecation != nil {
+		t.Errorf("inherited proxy route = %+v", r)
+	}
+	if r := got["GET /api/v1/users"]; r.Deprecation != deprecated {
+		t.Errorf("v1 route deprecation = %v, want the version deprecation", r.Deprecation)
+	}
+}
+
+func TestVersionsRequiresBase(t *testing.T) {
+	for _, base := range []string{"", "/"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Versions(%q) did not panic", base)
+				}
+			}()
+			Versions(base, APIVersion{Name: "v1"})
+		}()
+	}
+}
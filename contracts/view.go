@@ -0,0 +1,39 @@
+package contracts
+
+import "io"
+
+// View is a handler result rendered as HTML by the template feature. Return
+// it directly, or as the Body of a Response to also set headers and cookies.
+type View struct {
+	// Name is the template path relative to the template directory, without
+	// extension, e.g. "auth/verified"
+	Name string
+	// Layout overrides TEMPLATE_LAYOUT; NoLayout renders the template alone
+	Layout   string
+	NoLayout bool
+	Data     interface{}
+	// Status is the response status; zero uses 200 or the status of the Response
+	Status int
+}
+
+// NewView creates a view of the template name with data.
+func NewView(name string, data interface{}) *View {
+	return &View{Name: name, Data: data}
+}
+
+// WithLayout renders the view in layout instead of the default layout.
+func (v *View) WithLayout(layout string) *View {
+	v.Layout = layout
+	return v
+}
+
+// WithStatus sets the response status.
+func (v *View) WithStatus(status int) *View {
+	v.Status = status
+	return v
+}
+
+// ViewRenderer renders views. It is provided by the template feature.
+type ViewRenderer interface {
+	Render(w io.Writer, c *RequestContext, view *View) error
+}
@@ -0,0 +1,62 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shyandsy/aurora/bizerr"
+)
+
+// WebSocketMessage is the JSON frame exchanged on WebSocket routes. Type selects
+// the handler; ID, when set by the client, is echoed on the reply.
+type WebSocketMessage struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error *WebSocketError `json:"error,omitempty"`
+}
+
+// WebSocketError is the error of an "error" message. Code is the catalogue
+// code and Message is translated, as in HTTP error responses.
+type WebSocketError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// WebSocketHandlerFunc handles one message type. A non-nil result is sent back
+// with the same type and id; an error is sent back as an "error" message.
+type WebSocketHandlerFunc func(c *RequestContext, conn WebSocketConn, data json.RawMessage) (interface{}, bizerr.BizError)
+
+// WebSocketRoute registers a WebSocket endpoint. Middlewares run during the
+// HTTP handshake, before the upgrade, so authentication middlewares can reject
+// the connection with a normal error response.
+type WebSocketRoute struct {
+	Path        string
+	Middlewares []gin.HandlerFunc
+	Handlers    map[string]WebSocketHandlerFunc
+
+	OnConnect    func(c *RequestContext, conn WebSocketConn) bizerr.BizError
+	OnDisconnect func(c *RequestContext, conn WebSocketConn)
+}
+
+// WebSocketConn is a connected WebSocket client.
+type WebSocketConn interface {
+	ID() string
+	Send(msgType string, data interface{}) error
+	Join(room string)
+	Leave(room string)
+	Rooms() []string
+	Close() error
+	// Context is cancelled when the connection closes.
+	Context() context.Context
+}
+
+// WebSocketHub tracks connections and delivers broadcasts, across instances
+// when a broker is configured.
+type WebSocketHub interface {
+	Broadcast(msgType string, data interface{}) error
+	BroadcastToRoom(room, msgType string, data interface{}) error
+	Count() int
+}
@@ -1,12 +1,15 @@
 package feature
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/shyandsy/aurora/config"
 	"github.com/shyandsy/aurora/contracts"
+	auroraLogger "github.com/shyandsy/aurora/logger"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/sqlite"
@@ -62,7 +65,7 @@ func (f *gormFeature) Close() error {
 
 func (f *gormFeature) provideDatabase() (*gorm.DB, *sql.DB, error) {
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: &requestIDGormLogger{Interface: logger.Default.LogMode(logger.Info)},
 	}
 
 	var db *gorm.DB
@@ -95,3 +98,44 @@ func (f *gormFeature) provideDatabase() (*gorm.DB, *sql.DB, error) {
 
 	return db, sqlDB, nil
 }
+
+// requestIDGormLogger tags GORM log lines with the request ID of the query
+// context, set with db.WithContext(c.Request.Context())
+type requestIDGormLogger struct {
+	logger.Interface
+}
+
+func (l *requestIDGormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &requestIDGormLogger{Interface: l.Interface.LogMode(level)}
+}
+
+func (l *requestIDGormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Info(ctx, requestIDPrefix(ctx)+msg, data...)
+}
+
+func (l *requestIDGormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Warn(ctx, requestIDPrefix(ctx)+msg, data...)
+}
+
+func (l *requestIDGormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Error(ctx, requestIDPrefix(ctx)+msg, data...)
+}
+
+func (l *requestIDGormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	prefix := requestIDPrefix(ctx)
+	if prefix == "" {
+		l.Interface.Trace(ctx, begin, fc, err)
+		return
+	}
+	l.Interface.Trace(ctx, begin, func() (string, int64) {
+		sql, rows := fc()
+		return prefix + sql, rows
+	}, err)
+}
+
+func requestIDPrefix(ctx context.Context) string {
+	if requestID := auroraLogger.RequestID(ctx); requestID != "" {
+		return "[request_id=" + requestID + "] "
+	}
+	return ""
+}
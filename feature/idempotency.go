@@ -0,0 +1,323 @@
+package feature
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+const (
+	// IdempotencyKeyHeader is the default header carrying idempotency keys
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	idempotencyPollInterval = 100 * time.Millisecond
+
+	defaultIdempotencyMaxBodySize = 1 << 20
+)
+
+var (
+	errIdempotencyKeyMissing = errors.New("Idempotency-Key header is required")
+	errIdempotencyKeyInvalid = errors.New("Idempotency-Key header is too long")
+
+	CodeIdempotencyInFlight = bizerr.Register(bizerr.Definition{
+		Code: "idempotency.in_flight", HTTPCode: http.StatusConflict,
+		MessageID: "error.idempotency_in_flight", Message: "a request with this Idempotency-Key is still being processed",
+		Description: "A request with the same Idempotency-Key is still running, retry later",
+	})
+	CodeIdempotencyKeyReused = bizerr.Register(bizerr.Definition{
+		Code: "idempotency.key_reused", HTTPCode: http.StatusUnprocessableEntity,
+		MessageID: "error.idempotency_key_reused", Message: "Idempotency-Key was already used with a different request",
+		Description: "The Idempotency-Key was used before with a different method, path or body",
+	})
+)
+
+// IdempotencyOptions configures IdempotencyMiddleware
+type IdempotencyOptions struct {
+	// Header carries the key; defaults to Idempotency-Key
+	Header string
+	// Required rejects requests without a key with 400
+	Required bool
+	// TTL is how long responses are kept for replay; defaults to 24h
+	TTL time.Duration
+	// LockTTL bounds the lock held while the first request runs; it is
+	// refreshed while the handler runs. Defaults to 30s.
+	LockTTL time.Duration
+	// Wait is how long a concurrent duplicate waits for the first request
+	// before getting a 409; defaults to 10s
+	Wait time.Duration
+	// KeyPrefix prefixes the Redis keys; defaults to "idempotency"
+	KeyPrefix string
+	// MaxBodySize bounds the body buffered to fingerprint a request; larger
+	// bodies get a 413. Defaults to 1MiB.
+	MaxBodySize int64
+}
+
+// idempotencyRecord is the stored outcome of a request
+type idempotencyRecord struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// IdempotencyMiddleware makes POST, PUT, PATCH and DELETE requests carrying an
+// Idempotency-Key safe to retry. The response of the first request is stored
+// in Redis and replayed to retries with the same key and the same method, path
+// and body; a different request reusing the key gets a 422. Concurrent
+// duplicates are serialized with RedisService.WithLock and wait for the first
+// one to finish. Server errors (5xx) and 429s are not stored, so they can be
+// retried. Keys are scoped by route and by the user authenticated by
+// JWTAuthMiddleware, so add it after the authentication middleware.
+func IdempotencyMiddleware(app contracts.App, opts IdempotencyOptions) gin.HandlerFunc {
+	if opts.Header == "" {
+		opts.Header = IdempotencyKeyHeader
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = 30 * time.Second
+	}
+	if opts.Wait <= 0 {
+		opts.Wait = 10 * time.Second
+	}
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = "idempotency"
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = defaultIdempotencyMaxBodySize
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(opts.Header)
+		if key == "" {
+			if opts.Required {
+				contracts.AbortWithError(c, bizerr.ErrBadRequest(errIdempotencyKeyMissing))
+				return
+			}
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			contracts.AbortWithError(c, bizerr.ErrBadRequest(errIdempotencyKeyInvalid))
+			return
+		}
+
+		var redis RedisService
+		if err := app.Find(&redis); err != nil {
+			contracts.AbortWithError(c, bizerr.ErrInternalServerError(errors.New("idempotency requires the redis feature")))
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c, opts.MaxBodySize)
+		if err != nil {
+			contracts.AbortWithError(c, contracts.BodyError(err))
+			return
+		}
+
+		scope := "anonymous"
+		if userID, ok := c.Get(ContextKeyUserID); ok {
+			scope = fmt.Sprintf("user:%v", userID)
+		}
+		recordKey := opts.KeyPrefix + ":" + scope + ":" + c.Request.Method + ":" + c.FullPath() + ":" + key
+
+		ctx := c.Request.Context()
+		deadline := time.Now().Add(opts.Wait)
+		for {
+			record, err := loadIdempotencyRecord(ctx, redis, recordKey)
+			if err != nil {
+				contracts.AbortWithError(c, bizerr.ErrInternalServerError(err))
+				return
+			}
+			if record != nil {
+				replayIdempotencyRecord(c, record, fingerprint)
+				return
+			}
+
+			err = redis.WithLock(ctx, recordKey+":lock", newRequestID(), opts.LockTTL, func() error {
+				// The first request may have finished between the lookup and the lock
+				record, err := loadIdempotencyRecord(ctx, redis, recordKey)
+				if err != nil {
+					return err
+				}
+				if record != nil {
+					replayIdempotencyRecord(c, record, fingerprint)
+					return nil
+				}
+				return runIdempotent(c, redis, recordKey, fingerprint, opts.TTL)
+			})
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrLockNotAcquired) {
+				if !c.Writer.Written() {
+					contracts.AbortWithError(c, bizerr.ErrInternalServerError(err))
+				}
+				return
+			}
+
+			if time.Now().After(deadline) {
+				contracts.AbortWithError(c, CodeIdempotencyInFlight.New())
+				return
+			}
+			select {
+			case <-ctx.Done():
+				c.Abort()
+				return
+			case <-time.After(idempotencyPollInterval):
+			}
+		}
+	}
+}
+
+// requestFingerprint hashes the method, URL and body of the request, and
+// restores the body for the handler. Bodies over maxSize bytes are rejected
+// with an *http.MaxBytesError.
+func requestFingerprint(c *gin.Context, maxSize int64) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSize))
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func loadIdempotencyRecord(ctx context.Context, redis RedisService, key string) (*idempotencyRecord, error) {
+	exists, err := redis.Exists(ctx, key)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := redis.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	record := &idempotencyRecord{}
+	if err := json.Unmarshal([]byte(data), record); err != nil {
+		return nil, fmt.Errorf("invalid idempotency record %s: %w", key, err)
+	}
+	return record, nil
+}
+
+func replayIdempotencyRecord(c *gin.Context, record *idempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		contracts.AbortWithError(c, CodeIdempotencyKeyReused.New())
+		return
+	}
+
+	header := c.Writer.Header()
+	for name, values := range record.Header {
+		header[name] = values
+	}
+	header.Set(IdempotentReplayedHeader, "true")
+	c.Status(record.Status)
+	if len(record.Body) == 0 {
+		c.Writer.WriteHeaderNow()
+	} else {
+		_, _ = c.Writer.Write(record.Body)
+	}
+	c.Abort()
+}
+
+// runIdempotent runs the rest of the chain, recording the response, and stores it
+func runIdempotent(c *gin.Context, redis RedisService, key, fingerprint string, ttl time.Duration) error {
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Next()
+	c.Writer = recorder.ResponseWriter
+
+	// Errors of later middlewares aborting with contracts.AbortWithError are
+	// only rendered by abortedErrorMiddleware once the chain has returned
+	if !c.Writer.Written() || (c.IsAborted() && len(c.Errors) > 0) {
+		return nil
+	}
+	status := c.Writer.Status()
+	if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+		return nil
+	}
+
+	// The recorded body is written above the compression middleware, which
+	// compresses the replay again for the Accept-Encoding of the retry
+	header := c.Writer.Header().Clone()
+	for _, name := range []string{"Date", "Content-Length", "Vary", contracts.RequestIDHeader, "Set-Cookie"} {
+		header.Del(name)
+	}
+	if compressedBelow(c.Writer) {
+		header.Del("Content-Encoding")
+	}
+	data, err := json.Marshal(&idempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      status,
+		Header:      header,
+		Body:        recorder.body.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+	// The handler ran, so store its outcome even if the client went away
+	return redis.Set(context.WithoutCancel(c.Request.Context()), key, string(data), ttl)
+}
+
+// compressedBelow reports whether w writes through a compression writer that
+// compresses the response
+func compressedBelow(w http.ResponseWriter) bool {
+	for {
+		if cw, ok := w.(*compressWriter); ok {
+			return cw.compressor != nil
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// responseRecorder copies the response body while writing it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,230 @@
+package feature
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// memoryRedis implements the RedisService operations used by the idempotency middleware
+type memoryRedis struct {
+	RedisService
+	mu    sync.Mutex
+	data  map[string]string
+	locks map[string]bool
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{data: make(map[string]string), locks: make(map[string]bool)}
+}
+
+func (r *memoryRedis) Get(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found", key)
+	}
+	return value, nil
+}
+
+func (r *memoryRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (r *memoryRedis) Exists(ctx context.Context, key string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.data[key]
+	return ok, nil
+}
+
+func (r *memoryRedis) WithLock(ctx context.Context, key string, value string, ttl time.Duration, fn func() error) error {
+	r.mu.Lock()
+	if r.locks[key] {
+		r.mu.Unlock()
+		return ErrLockNotAcquired
+	}
+	r.locks[key] = true
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.locks, key)
+		r.mu.Unlock()
+	}()
+	return fn()
+}
+
+// newIdempotencyServer serves POST /orders behind the idempotency middleware
+// followed by the middlewares returned by chain, counting the handler calls
+func newIdempotencyServer(t *testing.T, calls *int, chain func(a contracts.App) []gin.HandlerFunc) *serverFeature {
+	t.Helper()
+
+	a := newTestApp(t)
+	if err := a.ProvideAs(newMemoryRedis(), (*RedisService)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	a.AddFeature(NewRateLimitFeature())
+
+	handlers := []gin.HandlerFunc{IdempotencyMiddleware(a, IdempotencyOptions{})}
+	if chain != nil {
+		handlers = append(handlers, chain(a)...)
+	}
+	return newTestServer(t, a, []contracts.Route{{
+		Method:      "POST",
+		Path:        "/orders",
+		Middlewares: handlers,
+		Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			*calls++
+			return contracts.Created("/orders/1", gin.H{"id": *calls, "note": strings.Repeat("x", 2048)}), nil
+		},
+	}})
+}
+
+func postOrder(key string, header ...string) *http.Request {
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"item":"book"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	return req
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	t.Setenv("RATE_LIMIT_STORE", "memory")
+	calls := 0
+	f := newIdempotencyServer(t, &calls, nil)
+
+	first := serve(f.Engine, postOrder("key-1"))
+	if first.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", first.Code)
+	}
+
+	replay := serve(f.Engine, postOrder("key-1"))
+	if replay.Code != http.StatusCreated || replay.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("replay status = %d, replayed = %q", replay.Code, replay.Header().Get(IdempotentReplayedHeader))
+	}
+	if replay.Body.String() != first.Body.String() || replay.Header().Get("Location") != "/orders/1" {
+		t.Fatalf("replay body = %q, want %q", replay.Body.String(), first.Body.String())
+	}
+	if calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", calls)
+	}
+
+	reused := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"item":"pen"}`))
+	reused.Header.Set(IdempotencyKeyHeader, "key-1")
+	if w := serve(f.Engine, reused); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key status = %d, want 422", w.Code)
+	}
+}
+
+func TestIdempotencyDoesNotStoreAbortedErrors(t *testing.T) {
+	t.Setenv("RATE_LIMIT_STORE", "memory")
+	calls := 0
+	requireAuth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			contracts.AbortWithError(c, bizerr.ErrUnauthorized())
+			return
+		}
+		c.Next()
+	}
+	byClient := func(c *gin.Context) string { return c.GetHeader("X-Client") }
+	f := newIdempotencyServer(t, &calls, func(a contracts.App) []gin.HandlerFunc {
+		return []gin.HandlerFunc{requireAuth, RateLimitMiddleware(a, RateLimit{Requests: 1, Window: time.Minute}, byClient)}
+	})
+
+	// A 401 aborted after the idempotency middleware is not replayed
+	if w := serve(f.Engine, postOrder("key-auth")); w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	w := serve(f.Engine, postOrder("key-auth", "Authorization", "Bearer token"))
+	if w.Code != http.StatusCreated || w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("retry status = %d, replayed = %q", w.Code, w.Header().Get(IdempotentReplayedHeader))
+	}
+
+	// A 429 of the rate limiter is not replayed as an empty 200
+	serve(f.Engine, postOrder("", "Authorization", "Bearer token", "X-Client", "c1"))
+	for i := 0; i < 2; i++ {
+		w := serve(f.Engine, postOrder("key-limited", "Authorization", "Bearer token", "X-Client", "c1"))
+		if w.Code != http.StatusTooManyRequests || w.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Fatalf("attempt %d: status = %d, replayed = %q", i, w.Code, w.Header().Get(IdempotentReplayedHeader))
+		}
+		if body := decodeBody(t, w); body["code"] != CodeRateLimited.Code {
+			t.Fatalf("attempt %d: code = %v", i, body["code"])
+		}
+	}
+}
+
+func TestIdempotencyReplayIsNotLabelledCompressed(t *testing.T) {
+	t.Setenv("RATE_LIMIT_STORE", "memory")
+	t.Setenv("COMPRESSION_ENABLED", "true")
+	calls := 0
+	f := newIdempotencyServer(t, &calls, nil)
+
+	first := serve(f.Engine, postOrder("key-gzip", "Accept-Encoding", "gzip"))
+	if first.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", first.Header().Get("Content-Encoding"))
+	}
+	gz, err := gzip.NewReader(first.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := io.ReadAll(gz)
+
+	replay := serve(f.Engine, postOrder("key-gzip"))
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatal("response was not replayed")
+	}
+	if encoding := replay.Header().Get("Content-Encoding"); encoding != "" {
+		t.Fatalf("replay Content-Encoding = %q, want none", encoding)
+	}
+	if replay.Body.String() != string(plain) {
+		t.Fatalf("replay body = %q, want %q", replay.Body.String(), plain)
+	}
+
+	compressed := serve(f.Engine, postOrder("key-gzip", "Accept-Encoding", "gzip"))
+	if compressed.Header().Get("Content-Encoding") != "gzip" || len(compressed.Header().Values("Vary")) != 1 {
+		t.Fatalf("replay headers = %v", compressed.Header())
+	}
+}
+
+func TestIdempotencyBodyLimit(t *testing.T) {
+	t.Setenv("RATE_LIMIT_STORE", "memory")
+	calls := 0
+	f := newIdempotencyServer(t, &calls, nil)
+
+	// MAX_BODY_SIZE is not set, so only the default of the middleware applies
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(strings.Repeat("x", defaultIdempotencyMaxBodySize+1)))
+	req.Header.Set(IdempotencyKeyHeader, "key-large")
+	w := serve(f.Engine, req)
+	if w.Code != http.StatusRequestEntityTooLarge || decodeBody(t, w)["code"] != bizerr.CodeRequestEntityTooLarge.Code {
+		t.Fatalf("large body: %d %s", w.Code, w.Body.String())
+	}
+	if calls != 0 {
+		t.Fatalf("handler calls = %d, want 0", calls)
+	}
+
+	// Bodies under the limit still reach the handler
+	if w := serve(f.Engine, postOrder("key-small")); w.Code != http.StatusCreated || calls != 1 {
+		t.Fatalf("small body: %d, %d calls", w.Code, calls)
+	}
+}
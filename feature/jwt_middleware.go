@@ -0,0 +1,105 @@
+package feature
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/websocket"
+)
+
+const (
+	// ContextKeyClaims is the key for storing the validated *Claims in gin.Context
+	ContextKeyClaims = "jwt_claims"
+	// ContextKeyUserID is the key for storing user ID in gin.Context
+	ContextKeyUserID = "user_id"
+	// ContextKeyUserEmail is the key for storing user email in gin.Context
+	ContextKeyUserEmail = "user_email"
+
+	// accessTokenQueryParam carries the token on WebSocket handshakes, since
+	// browsers cannot set the Authorization header there
+	accessTokenQueryParam = "access_token"
+)
+
+// JWTAuthMiddleware creates a JWT authentication middleware
+// It extracts the Bearer token from the Authorization header, or from the
+// access_token query parameter on WebSocket upgrade requests, validates it,
+// and stores the claims in context
+// If features are provided, the user must have all of them
+// Failures abort with bizerr.ErrUnauthorized (401) or bizerr.ErrForbidden
+// (403), rendered by the error handler
+func JWTAuthMiddleware(app contracts.App, features ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var jwtService JWTService
+		if err := app.Find(&jwtService); err != nil {
+			contracts.AbortWithError(c, bizerr.ErrInternalServerError(errors.New("JWT service not available")))
+			return
+		}
+
+		tokenString, ok := bearerToken(c)
+		if !ok {
+			contracts.AbortWithError(c, bizerr.ErrUnauthorized())
+			return
+		}
+
+		claims, err := jwtService.ValidateToken(tokenString)
+		if err != nil {
+			contracts.AbortWithError(c, bizerr.ErrUnauthorized())
+			return
+		}
+
+		for _, required := range features {
+			if !hasFeature(claims, required) {
+				contracts.AbortWithError(c, bizerr.ErrForbidden())
+				return
+			}
+		}
+
+		c.Set(ContextKeyClaims, claims)
+		c.Set(ContextKeyUserID, claims.UserID)
+		c.Set(ContextKeyUserEmail, claims.Email)
+
+		c.Next()
+	}
+}
+
+// GetClaims returns the claims stored by JWTAuthMiddleware
+func GetClaims(c *gin.Context) (*Claims, bool) {
+	value, exists := c.Get(ContextKeyClaims)
+	if !exists {
+		return nil, false
+	}
+
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+			return "", false
+		}
+		return parts[1], true
+	}
+
+	if websocket.IsUpgradeRequest(c.Request) {
+		if token := c.Query(accessTokenQueryParam); token != "" {
+			return token, true
+		}
+	}
+
+	return "", false
+}
+
+func hasFeature(claims *Claims, feature string) bool {
+	for _, f := range claims.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
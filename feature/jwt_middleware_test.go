@@ -0,0 +1,75 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_EXPIRE_TIME", "1h")
+	t.Setenv("JWT_ISSUER", "test")
+
+	a := newTestApp(t)
+	if err := a.ProvideAs(newMemoryRedis(), (*RedisService)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	a.AddFeature(NewJWTFeature())
+	var jwtService JWTService
+	if err := a.Find(&jwtService); err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwtService.GenerateToken(42, "ann@example.com", []string{"user.get"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		return gin.H{"user_id": c.GetInt64(ContextKeyUserID)}, nil
+	}
+	f := newTestServer(t, a, []contracts.Route{
+		{Method: "GET", Path: "/users", Handler: handler, Middlewares: []gin.HandlerFunc{JWTAuthMiddleware(a, "user.get")}},
+		{Method: "DELETE", Path: "/users", Handler: handler, Middlewares: []gin.HandlerFunc{JWTAuthMiddleware(a, "user.delete")}},
+	})
+
+	tests := []struct {
+		method        string
+		authorization string
+		status        int
+		code          string
+	}{
+		{"GET", "", http.StatusUnauthorized, bizerr.CodeUnauthorized.Code},
+		{"GET", "Token " + token.AccessToken, http.StatusUnauthorized, bizerr.CodeUnauthorized.Code},
+		{"GET", "Bearer invalid", http.StatusUnauthorized, bizerr.CodeUnauthorized.Code},
+		{"DELETE", "Bearer " + token.AccessToken, http.StatusForbidden, bizerr.CodeForbidden.Code},
+		{"GET", "Bearer " + token.AccessToken, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/users", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		w := serve(f.Engine, req)
+		body := decodeBody(t, w)
+		if w.Code != tt.status {
+			t.Errorf("%s %q: status = %d, want %d", tt.method, tt.authorization, w.Code, tt.status)
+			continue
+		}
+		if tt.code == "" {
+			if body["user_id"] != float64(42) {
+				t.Errorf("%s %q: body = %v", tt.method, tt.authorization, body)
+			}
+			continue
+		}
+		// Errors are rendered by the error handler, like the errors of handlers
+		if body["code"] != tt.code || body["request_id"] == "" || body["request_id"] != w.Header().Get(contracts.RequestIDHeader) {
+			t.Errorf("%s %q: body = %v", tt.method, tt.authorization, body)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/shyandsy/aurora/config"
 	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
 )
 
 // EmailService provides email sending functionality
@@ -93,6 +94,11 @@ func (s *mailService) send(ctx context.Context, to []string, subject, textBody,
 	// Set Subject
 	m.SetHeader("Subject", subject)
 
+	// Correlate the email with the request that sent it
+	if requestID := logger.RequestID(ctx); requestID != "" {
+		m.SetHeader(contracts.RequestIDHeader, requestID)
+	}
+
 	// Set body
 	if textBody != "" && htmlBody != "" {
 		m.SetBody("text/plain", textBody)
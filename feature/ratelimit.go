@@ -0,0 +1,255 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// Rate limit algorithms
+const (
+	// RateLimitFixedWindow counts requests in consecutive windows. Cheap, but
+	// allows bursts of up to twice the limit around window boundaries.
+	RateLimitFixedWindow = "fixed_window"
+	// RateLimitSlidingWindow keeps a log of request times over the last window.
+	// Exact, at the cost of one entry per request.
+	RateLimitSlidingWindow = "sliding_window"
+	// RateLimitTokenBucket refills Requests tokens per Window up to Burst,
+	// allowing short bursts while enforcing the average rate.
+	RateLimitTokenBucket = "token_bucket"
+)
+
+var CodeRateLimited = bizerr.Register(bizerr.Definition{
+	Code: "rate_limited", HTTPCode: http.StatusTooManyRequests,
+	MessageID: "error.rate_limited", Message: "too many requests",
+	Description: "The rate limit of the route is exceeded, retry after the Retry-After header",
+})
+
+// RateLimit describes a limit of Requests per Window
+type RateLimit struct {
+	// Name scopes the counters, so that routes sharing a name share a limit.
+	// Empty uses the route path.
+	Name      string
+	Algorithm string
+	Requests  int
+	Window    time.Duration
+	// Burst is the token bucket capacity. Zero uses Requests.
+	Burst int
+}
+
+// Validate reports whether the limit can be enforced
+func (l RateLimit) Validate() error {
+	if l.Requests <= 0 || l.Window <= 0 {
+		return errors.New("rate limit requires positive Requests and Window")
+	}
+	if l.Burst < 0 {
+		return errors.New("rate limit Burst must not be negative")
+	}
+	switch l.Algorithm {
+	case RateLimitFixedWindow, RateLimitSlidingWindow, RateLimitTokenBucket, "":
+		return nil
+	default:
+		return fmt.Errorf("unknown rate limit algorithm %q", l.Algorithm)
+	}
+}
+
+// RateLimitResult is the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the limit is fully available again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, when not Allowed
+	RetryAfter time.Duration
+}
+
+// RateLimiter checks rate limits
+type RateLimiter interface {
+	// Allow records a request for key and reports whether it is within limit
+	Allow(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error)
+}
+
+// rateLimitStore implements the algorithms on a counter store
+type rateLimitStore interface {
+	fixedWindow(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error)
+	slidingWindow(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error)
+	tokenBucket(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error)
+}
+
+// RateLimitKeyFunc returns the client key a limit applies to. An empty key skips the limit.
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP limits each client IP
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser limits each user authenticated by JWTAuthMiddleware, and
+// anonymous clients by IP
+func RateLimitByUser(c *gin.Context) string {
+	if userID, ok := c.Get(ContextKeyUserID); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByAPIKey limits each API key sent in header; requests without one are not limited
+func RateLimitByAPIKey(header string) RateLimitKeyFunc {
+	return func(c *gin.Context) string {
+		if key := c.GetHeader(header); key != "" {
+			return "apikey:" + key
+		}
+		return ""
+	}
+}
+
+type rateLimitFeature struct {
+	config   *config.RateLimitConfig
+	store    rateLimitStore
+	fallback *memoryRateLimitStore
+}
+
+func NewRateLimitFeature() contracts.Features {
+	cfg := &config.RateLimitConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		log.Fatalf("Failed to load rate limit config: %v", err)
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "ratelimit"
+	}
+	return &rateLimitFeature{config: cfg}
+}
+
+func (f *rateLimitFeature) Name() string {
+	return "ratelimit"
+}
+
+func (f *rateLimitFeature) Setup(app contracts.App) error {
+	if err := f.config.Validate(); err != nil {
+		return fmt.Errorf("rate limit configuration validation failed: %w", err)
+	}
+
+	f.fallback = newMemoryRateLimitStore()
+	f.store = f.fallback
+
+	if f.config.Store != config.RateLimitStoreMemory {
+		var redis RedisService
+		if err := app.Find(&redis); err == nil {
+			f.store = &redisRateLimitStore{redis: redis}
+		} else if f.config.Store == config.RateLimitStoreRedis {
+			return fmt.Errorf("RATE_LIMIT_STORE=redis requires the redis feature: %w", err)
+		}
+	}
+
+	if err := app.ProvideAs(f, (*RateLimiter)(nil)); err != nil {
+		return fmt.Errorf("failed to register RateLimiter: %w", err)
+	}
+	return nil
+}
+
+func (f *rateLimitFeature) Close() error {
+	f.fallback.stop()
+	return nil
+}
+
+// Allow checks limit in the configured store. When Redis fails, the check
+// falls back to the in-memory store, so limits still apply per instance.
+func (f *rateLimitFeature) Allow(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	key = f.config.KeyPrefix + ":" + limit.Algorithm + ":" + limit.Name + ":" + key
+
+	result, err := f.allow(ctx, f.store, key, limit)
+	if err != nil && f.store != rateLimitStore(f.fallback) {
+		logger.ErrorContext(ctx, "rate limit store failed, using in-memory fallback: %v", err)
+		return f.allow(ctx, f.fallback, key, limit)
+	}
+	return result, err
+}
+
+func (f *rateLimitFeature) allow(ctx context.Context, store rateLimitStore, key string, limit RateLimit) (*RateLimitResult, error) {
+	now := time.Now()
+	switch limit.Algorithm {
+	case RateLimitFixedWindow, "":
+		return store.fixedWindow(ctx, key, limit, now)
+	case RateLimitSlidingWindow:
+		return store.slidingWindow(ctx, key, limit, now)
+	case RateLimitTokenBucket:
+		return store.tokenBucket(ctx, key, limit, now)
+	default:
+		return nil, fmt.Errorf("unknown rate limit algorithm %q", limit.Algorithm)
+	}
+}
+
+// RateLimitMiddleware limits requests per key, as returned by keyFunc
+// (RateLimitByIP when nil). Responses carry RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers; rejected requests get a 429 with Retry-After.
+// Requires the rate limit feature. It panics when limit is invalid, so
+// mistakes show up when the routes are built rather than on requests.
+func RateLimitMiddleware(app contracts.App, limit RateLimit, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	if err := limit.Validate(); err != nil {
+		panic(fmt.Errorf("invalid rate limit %q: %w", limit.Name, err))
+	}
+	if keyFunc == nil {
+		keyFunc = RateLimitByIP
+	}
+
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		var limiter RateLimiter
+		if err := app.Find(&limiter); err != nil {
+			contracts.AbortWithError(c, bizerr.ErrInternalServerError(errors.New("rate limiter not available")))
+			return
+		}
+
+		routeLimit := limit
+		if routeLimit.Name == "" {
+			routeLimit.Name = c.FullPath()
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), key, routeLimit)
+		if err != nil {
+			contracts.AbortWithError(c, bizerr.ErrInternalServerError(err))
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			contracts.AbortWithError(c, CodeRateLimited.New())
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
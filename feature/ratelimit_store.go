@@ -0,0 +1,309 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// fixedWindowScript increments the window counter, starting the window on
+	// the first request. Returns {count, ttl_ms}.
+	fixedWindowScript = `
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`
+
+	// slidingWindowScript keeps a sorted set of request times. ARGV: now_ms,
+	// window_ms, limit, member. Returns {allowed, count, reset_ms}.
+	slidingWindowScript = `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`
+
+	// tokenBucketScript refills the bucket for the elapsed time and takes a
+	// token. ARGV: capacity, tokens_per_ms, now_ms. Returns {allowed, tokens,
+	// retry_ms, reset_ms}.
+	tokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+local retry = 0
+if allowed == 0 then
+	retry = math.ceil((1 - tokens) / rate)
+end
+return {allowed, math.floor(tokens), retry, math.ceil((capacity - tokens) / rate)}
+`
+)
+
+// redisRateLimitStore runs each algorithm as one atomic Lua script, so that
+// all instances share the limits
+type redisRateLimitStore struct {
+	redis RedisService
+}
+
+func (s *redisRateLimitStore) fixedWindow(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error) {
+	values, err := s.eval(ctx, fixedWindowScript, key, windowMillis(limit.Window))
+	if err != nil {
+		return nil, err
+	}
+	return fixedWindowResult(limit, int(values[0]), time.Duration(values[1])*time.Millisecond), nil
+}
+
+func (s *redisRateLimitStore) slidingWindow(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error) {
+	values, err := s.eval(ctx, slidingWindowScript, key, now.UnixMilli(), windowMillis(limit.Window), limit.Requests, strconv.FormatInt(now.UnixNano(), 10)+"-"+randomHex(8))
+	if err != nil {
+		return nil, err
+	}
+	return slidingWindowResult(limit, values[0] == 1, int(values[1]), time.Duration(values[2])*time.Millisecond), nil
+}
+
+func (s *redisRateLimitStore) tokenBucket(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error) {
+	capacity := tokenBucketCapacity(limit)
+	rate := float64(limit.Requests) / float64(windowMillis(limit.Window))
+	values, err := s.eval(ctx, tokenBucketScript, key, capacity, strconv.FormatFloat(rate, 'g', -1, 64), now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      capacity,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// windowMillis returns w in milliseconds, the resolution of the scripts,
+// rounding up so that sub-millisecond windows do not become zero
+func windowMillis(w time.Duration) int64 {
+	return int64((w + time.Millisecond - 1) / time.Millisecond)
+}
+
+func (s *redisRateLimitStore) eval(ctx context.Context, script, key string, args ...interface{}) ([]int64, error) {
+	result, err := s.redis.Eval(ctx, script, []string{key}, args...)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected rate limit script result %T", result)
+	}
+	values := make([]int64, len(items))
+	for i, item := range items {
+		value, ok := item.(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected rate limit script result %T", item)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// memoryRateLimitStore keeps the counters in memory, for single instance
+// deployments and as fallback when Redis is unavailable
+type memoryRateLimitStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryRateLimitEntry
+	done    chan struct{}
+	once    sync.Once
+}
+
+type memoryRateLimitEntry struct {
+	expires time.Time
+	// fixed window
+	count int
+	// sliding window log
+	log []time.Time
+	// token bucket
+	tokens float64
+	last   time.Time
+}
+
+func newMemoryRateLimitStore() *memoryRateLimitStore {
+	s := &memoryRateLimitStore{
+		entries: make(map[string]*memoryRateLimitEntry),
+		done:    make(chan struct{}),
+	}
+	go s.cleanup(time.Minute)
+	return s
+}
+
+func (s *memoryRateLimitStore) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// cleanup drops expired entries periodically
+func (s *memoryRateLimitStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if now.After(entry.expires) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// entry returns the live entry for key, creating it if missing or expired
+func (s *memoryRateLimitStore) entry(key string, now time.Time) *memoryRateLimitEntry {
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = &memoryRateLimitEntry{}
+		s.entries[key] = entry
+	}
+	return entry
+}
+
+func (s *memoryRateLimitStore) fixedWindow(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, now)
+	if entry.count == 0 {
+		entry.expires = now.Add(limit.Window)
+	}
+	entry.count++
+	return fixedWindowResult(limit, entry.count, entry.expires.Sub(now)), nil
+}
+
+func (s *memoryRateLimitStore) slidingWindow(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, now)
+	cutoff := now.Add(-limit.Window)
+	kept := entry.log[:0]
+	for _, t := range entry.log {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	entry.log = kept
+
+	allowed := len(entry.log) < limit.Requests
+	if allowed {
+		entry.log = append(entry.log, now)
+		entry.expires = now.Add(limit.Window)
+	}
+
+	var reset time.Duration
+	if len(entry.log) > 0 {
+		reset = entry.log[0].Add(limit.Window).Sub(now)
+	}
+	return slidingWindowResult(limit, allowed, len(entry.log), reset), nil
+}
+
+func (s *memoryRateLimitStore) tokenBucket(ctx context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	capacity := float64(tokenBucketCapacity(limit))
+	rate := float64(limit.Requests) / float64(limit.Window) // tokens per nanosecond
+
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = &memoryRateLimitEntry{tokens: capacity, last: now}
+		s.entries[key] = entry
+	}
+	entry.tokens = math.Min(capacity, entry.tokens+float64(now.Sub(entry.last))*rate)
+	entry.last = now
+
+	result := &RateLimitResult{Limit: int(capacity)}
+	if entry.tokens >= 1 {
+		entry.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - entry.tokens) / rate))
+	}
+	result.Remaining = int(math.Floor(entry.tokens))
+	result.Reset = time.Duration(math.Ceil((capacity - entry.tokens) / rate))
+	entry.expires = now.Add(time.Duration(math.Ceil(capacity / rate)))
+	return result, nil
+}
+
+func fixedWindowResult(limit RateLimit, count int, ttl time.Duration) *RateLimitResult {
+	result := &RateLimitResult{
+		Allowed:   count <= limit.Requests,
+		Limit:     limit.Requests,
+		Remaining: limit.Requests - count,
+		Reset:     ttl,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !result.Allowed {
+		result.RetryAfter = ttl
+	}
+	return result
+}
+
+func slidingWindowResult(limit RateLimit, allowed bool, count int, reset time.Duration) *RateLimitResult {
+	result := &RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: limit.Requests - count,
+		Reset:     reset,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	if !allowed {
+		result.RetryAfter = reset
+	}
+	return result
+}
+
+func tokenBucketCapacity(limit RateLimit) int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	return limit.Requests
+}
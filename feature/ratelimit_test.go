@@ -0,0 +1,126 @@
+package feature
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// evalRedis records the arguments of the rate limit scripts
+type evalRedis struct {
+	RedisService
+	args []interface{}
+}
+
+func (r *evalRedis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	r.args = args
+	return []interface{}{int64(1), int64(0), int64(0), int64(0)}, nil
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	tests := []struct {
+		limit RateLimit
+		valid bool
+	}{
+		{RateLimit{Requests: 10, Window: time.Second}, true},
+		{RateLimit{Requests: 10, Window: time.Second, Algorithm: RateLimitTokenBucket, Burst: 20}, true},
+		{RateLimit{Requests: 0, Window: time.Second}, false},
+		{RateLimit{Requests: 10}, false},
+		{RateLimit{Requests: 10, Window: time.Second, Burst: -1}, false},
+		{RateLimit{Requests: 10, Window: time.Second, Algorithm: "leaky_bucket"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.limit.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%+v: Validate() = %v", tt.limit, err)
+		}
+	}
+}
+
+func TestRateLimitMiddlewarePanicsOnInvalidLimit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RateLimitMiddleware did not panic")
+		}
+	}()
+	RateLimitMiddleware(nil, RateLimit{Requests: 10}, nil)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Setenv("RATE_LIMIT_STORE", "memory")
+
+	a := newTestApp(t)
+	a.AddFeature(NewRateLimitFeature())
+	ok := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		return gin.H{"ok": true}, nil
+	}
+	f := newTestServer(t, a, []contracts.Route{
+		{Method: "GET", Path: "/fixed", Handler: ok, Middlewares: []gin.HandlerFunc{
+			RateLimitMiddleware(a, RateLimit{Requests: 2, Window: time.Minute}, nil),
+		}},
+		{Method: "GET", Path: "/bucket", Handler: ok, Middlewares: []gin.HandlerFunc{
+			RateLimitMiddleware(a, RateLimit{Algorithm: RateLimitTokenBucket, Requests: 1, Window: time.Minute, Burst: 1}, nil),
+		}},
+	})
+
+	for _, path := range []string{"/fixed", "/fixed", "/bucket"} {
+		if w := serve(f.Engine, httptest.NewRequest("GET", path, nil)); w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", path, w.Code)
+		}
+	}
+
+	for _, path := range []string{"/fixed", "/bucket"} {
+		w := serve(f.Engine, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("%s: status = %d, want 429", path, w.Code)
+			continue
+		}
+		if w.Header().Get("RateLimit-Remaining") != "0" || w.Header().Get("Retry-After") == "" {
+			t.Errorf("%s: headers = %v", path, w.Header())
+		}
+		if body := decodeBody(t, w); body["code"] != CodeRateLimited.Code {
+			t.Errorf("%s: body = %v", path, body)
+		}
+	}
+
+	// Other clients have their own counters
+	req := httptest.NewRequest("GET", "/fixed", nil)
+	req.RemoteAddr = "192.0.2.2:1234"
+	if w := serve(f.Engine, req); w.Code != http.StatusOK {
+		t.Errorf("other client: status = %d", w.Code)
+	}
+}
+
+func TestRedisRateLimitStoreSubMillisecondWindow(t *testing.T) {
+	redis := &evalRedis{}
+	store := &redisRateLimitStore{redis: redis}
+	limit := RateLimit{Requests: 2, Window: 500 * time.Microsecond}
+	now := time.Now()
+
+	if _, err := store.fixedWindow(context.Background(), "k", limit, now); err != nil {
+		t.Fatal(err)
+	}
+	if redis.args[0] != int64(1) {
+		t.Errorf("fixed window ms = %v, want 1", redis.args[0])
+	}
+
+	if _, err := store.slidingWindow(context.Background(), "k", limit, now); err != nil {
+		t.Fatal(err)
+	}
+	if redis.args[1] != int64(1) {
+		t.Errorf("sliding window ms = %v, want 1", redis.args[1])
+	}
+
+	if _, err := store.tokenBucket(context.Background(), "k", limit, now); err != nil {
+		t.Fatal(err)
+	}
+	if redis.args[1] != "2" {
+		t.Errorf("token bucket rate = %v, want 2 tokens per ms", redis.args[1])
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 
 	"github.com/shyandsy/aurora/config"
 	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
 )
 
 type RedisService interface {
@@ -35,6 +37,13 @@ type RedisService interface {
 	// If the context is cancelled, the lock is automatically released
 	// The lock will automatically expire after ttl duration if the process crashes
 	WithLock(ctx context.Context, key string, value string, ttl time.Duration, fn func() error) error
+	// Eval runs a Lua script atomically. The script is cached on the server and
+	// invoked with EVALSHA after the first call.
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+	// Pub/Sub operations
+	Publish(ctx context.Context, channel string, message interface{}) error
+	// Subscribe calls fn for every message published to channel until ctx is cancelled
+	Subscribe(ctx context.Context, channel string, fn func(payload string)) error
 }
 
 type redisFeature struct {
@@ -84,6 +93,8 @@ func (f *redisFeature) provideRedis() (*redis.Client, error) {
 		DB:       f.config.DB,
 	})
 
+	client.AddHook(redisLogHook{})
+
 	ctx := context.Background()
 	if err := client.Ping(ctx).Err(); err != nil {
 		return nil, err
@@ -92,8 +103,52 @@ func (f *redisFeature) provideRedis() (*redis.Client, error) {
 	return client, nil
 }
 
+type redisStartKey struct{}
+
+// redisLogHook logs Redis commands at debug level and failures at error level,
+// tagged with the request ID of the command context
+type redisLogHook struct{}
+
+func (redisLogHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (redisLogHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	logRedisCommand(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (redisLogHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (redisLogHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && cmdErr != redis.Nil {
+			err = cmdErr
+			break
+		}
+	}
+	logRedisCommand(ctx, fmt.Sprintf("pipeline (%d commands)", len(cmds)), err)
+	return nil
+}
+
+func logRedisCommand(ctx context.Context, name string, err error) {
+	var elapsed time.Duration
+	if start, ok := ctx.Value(redisStartKey{}).(time.Time); ok {
+		elapsed = time.Since(start)
+	}
+	if err != nil && err != redis.Nil {
+		logger.ErrorContext(ctx, "redis %s failed after %v: %v", name, elapsed, err)
+		return
+	}
+	logger.DebugContext(ctx, "redis %s (%v)", name, elapsed)
+}
+
 type redisService struct {
-	client *redis.Client
+	client  *redis.Client
+	scripts sync.Map // script source -> *redis.Script
 }
 
 func (r *redisService) Get(ctx context.Context, key string) (string, error) {
@@ -168,6 +223,48 @@ func (r *redisService) Expire(ctx context.Context, key string, expiration time.D
 	return r.client.Expire(ctx, key, expiration).Err()
 }
 
+// Eval runs a Lua script atomically, using EVALSHA once the script is cached
+func (r *redisService) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	cached, ok := r.scripts.Load(script)
+	if !ok {
+		cached, _ = r.scripts.LoadOrStore(script, redis.NewScript(script))
+	}
+	result, err := cached.(*redis.Script).Run(ctx, r.client, keys, args...).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return result, err
+}
+
+// Publish posts a message to channel
+func (r *redisService) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe calls fn for every message published to channel until ctx is cancelled
+func (r *redisService) Subscribe(ctx context.Context, channel string, fn func(payload string)) error {
+	pubsub := r.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed so that errors surface here
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("subscription to %s closed", channel)
+			}
+			fn(msg.Payload)
+		}
+	}
+}
+
 // ErrLockNotAcquired is returned when WithLock cannot acquire the lock
 var ErrLockNotAcquired = fmt.Errorf("lock not acquired")
 
@@ -0,0 +1,76 @@
+package feature
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// maxRequestIDLength bounds incoming X-Request-ID values
+const maxRequestIDLength = 128
+
+// requestIDMiddleware accepts the X-Request-ID of the request, or generates one,
+// and stores it in the request context and the response header
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(contracts.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(contracts.RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII IDs without spaces, so that client
+// supplied values cannot inject content into logs or headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random UUID v4
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// requestIDTransport sets X-Request-ID on outgoing requests from the request ID of their context
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if requestID := logger.RequestID(req.Context()); requestID != "" && req.Header.Get(contracts.RequestIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(contracts.RequestIDHeader, requestID)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// NewHTTPClient returns an HTTP client that forwards the request ID of each
+// request's context as X-Request-ID. A zero timeout means no timeout.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &requestIDTransport{base: http.DefaultTransport},
+	}
+}
@@ -2,34 +2,60 @@ package feature
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/shyandsy/aurora/bizerr"
 	"github.com/shyandsy/aurora/config"
 	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+	"github.com/shyandsy/aurora/openapi"
+	"github.com/shyandsy/aurora/websocket"
 )
 
 type serverFeature struct {
-	App          contracts.App
-	Config       *config.ServerConfig `inject:""`
-	Engine       *gin.Engine
-	server       *http.Server
-	routes       []contracts.Route
-	errorHandler contracts.ErrorHandler
-	stopChan     chan os.Signal
-	running      bool
-	mu           sync.Mutex
-	wg           sync.WaitGroup
+	App               contracts.App
+	Config            *config.ServerConfig `inject:""`
+	Engine            *gin.Engine
+	server            *http.Server
+	listener          net.Listener
+	routes            []contracts.Route
+	errorHandler      contracts.ErrorHandler
+	envelope          contracts.EnvelopeFunc
+	panicReporter     PanicReporter
+	openAPIConfig     *config.OpenAPIConfig
+	responseConfig    *config.ResponseConfig
+	compressionConfig *config.CompressionConfig
+	securityConfig    *config.SecurityConfig
+	apiVersionConfig  *config.APIVersionConfig
+	httpCacheConfig   *config.HTTPCacheConfig
+	accessLogConfig   *config.AccessLogConfig
+	responseCache     *responseCache
+	deprecationHook   DeprecationHook
+	corsOverrides     map[string]bool
+	encoders          map[string]CompressionEncoder
+	wsRoutes          []contracts.WebSocketRoute
+	staticSites       []*staticSite
+	wsConfig          *config.WebSocketConfig
+	hub               *websocket.Hub
+	upgrader          *websocket.Upgrader
+	stopChan          chan os.Signal
+	shutdownCtx       context.Context
+	cancelStreams     context.CancelFunc
+	running           bool
+	mu                sync.Mutex
+	wg                sync.WaitGroup
 }
 
 // ServerOption configures a ServerFeature.
@@ -47,6 +73,7 @@ func NewServerFeature(opts ...ServerOption) contracts.ServerFeature {
 		stopChan: make(chan os.Signal, 1),
 		running:  false,
 	}
+	f.shutdownCtx, f.cancelStreams = context.WithCancel(context.Background())
 	for _, opt := range opts {
 		opt(f)
 	}
@@ -67,12 +94,52 @@ func (f *serverFeature) Setup(app contracts.App) error {
 		return fmt.Errorf("server configuration validation failed: %w", err)
 	}
 
+	if err := f.loadOpenAPIConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadResponseConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadWebSocketConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadCompressionConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadSecurityConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadAPIVersionConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadHTTPCacheConfig(); err != nil {
+		return err
+	}
+
+	if err := f.loadAccessLogConfig(); err != nil {
+		return err
+	}
+
 	f.Engine = f.createGinEngine()
 
 	if err := app.Provide(f.Engine); err != nil {
 		return fmt.Errorf("failed to register gin.Engine: %w", err)
 	}
 
+	if err := app.ProvideAs(f.hub, (*contracts.WebSocketHub)(nil)); err != nil {
+		return fmt.Errorf("failed to register WebSocketHub: %w", err)
+	}
+
+	if err := app.ProvideAs(f.responseCache, (*contracts.ResponseCache)(nil)); err != nil {
+		return fmt.Errorf("failed to register ResponseCache: %w", err)
+	}
+
 	return nil
 }
 
@@ -88,8 +155,22 @@ func (f *serverFeature) Start() error {
 		return nil
 	}
 
-	f.setupRoutes()
-	f.server = f.createServer()
+	if err := f.setupRoutes(); err != nil {
+		return err
+	}
+	if err := f.setupWebSockets(); err != nil {
+		return err
+	}
+	server, err := f.createServer()
+	if err != nil {
+		return err
+	}
+	ln, err := f.listen()
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	f.server = server
+	f.listener = ln
 	f.running = true
 
 	f.wg.Add(1)
@@ -98,6 +179,9 @@ func (f *serverFeature) Start() error {
 	f.wg.Add(1)
 	go f.signalListener()
 
+	// Let the parent of a graceful restart drain and exit
+	notifyRestartReady()
+
 	return nil
 }
 
@@ -123,16 +207,29 @@ func (f *serverFeature) Wait() {
 	f.wg.Wait()
 }
 
-func (f *serverFeature) createServer() *http.Server {
+func (f *serverFeature) createServer() (*http.Server, error) {
 	addr := f.Config.Host + ":" + strconv.Itoa(f.Config.Port)
 
+	tlsConfig, err := f.createTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	// Handler wraps the engine with h2c when H2C_ENABLED is set
+	f.Engine.UseH2C = f.Config.H2C
+	handler := f.Engine.Handler()
+	if f.apiVersionConfig.Strategy != config.APIVersionByPath {
+		handler = f.apiVersionHandler(handler)
+	}
+
 	return &http.Server{
 		Addr:         addr,
-		Handler:      f.Engine,
+		Handler:      handler,
+		TLSConfig:    tlsConfig,
 		ReadTimeout:  f.Config.ReadTimeout,
 		WriteTimeout: f.Config.WriteTimeout,
 		IdleTimeout:  f.Config.IdleTimeout,
-	}
+	}, nil
 }
 
 func (f *serverFeature) startServer() {
@@ -144,9 +241,17 @@ func (f *serverFeature) startServer() {
 	}
 
 	fmt.Println()
-	log.Printf("Starting server on %s", f.server.Addr)
+	var err error
+	if f.server.TLSConfig != nil {
+		log.Printf("Starting server on %s (TLS)", f.listener.Addr())
+		// The certificate is served by TLSConfig.GetCertificate
+		err = f.server.ServeTLS(f.listener, "", "")
+	} else {
+		log.Printf("Starting server on %s", f.listener.Addr())
+		err = f.server.Serve(f.listener)
+	}
 
-	if err := f.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err != nil && err != http.ErrServerClosed {
 		panic(fmt.Errorf("Server start failed: %w", err))
 	}
 }
@@ -155,8 +260,23 @@ func (f *serverFeature) signalListener() {
 	defer f.wg.Done()
 
 	signal.Notify(f.stopChan, syscall.SIGINT, syscall.SIGTERM)
-	<-f.stopChan
-	f.shutdownServer()
+	restart := f.restartSignals()
+
+	for {
+		select {
+		case <-f.stopChan:
+			f.shutdownServer()
+			return
+		case <-restart:
+			log.Printf("Restart requested, starting new process")
+			if err := f.restart(); err != nil {
+				log.Printf("Restart failed, keeping the current process: %v", err)
+				continue
+			}
+			f.shutdownServer()
+			return
+		}
+	}
 }
 
 func (f *serverFeature) shutdownServer() error {
@@ -168,6 +288,11 @@ func (f *serverFeature) shutdownServer() error {
 	server := f.server
 	f.mu.Unlock()
 
+	// End streaming responses first, otherwise Shutdown waits for them until the timeout
+	f.cancelStreams()
+	// Hijacked WebSocket connections are not tracked by Shutdown
+	f.hub.Close()
+
 	ctx, cancel := context.WithTimeout(context.Background(), f.Config.ShutdownTimeout)
 	defer cancel()
 
@@ -199,39 +324,37 @@ func (f *serverFeature) createGinEngine() *gin.Engine {
 	}
 
 	engine := gin.New()
-	engine.Use(gin.Logger())
-	engine.Use(gin.Recovery())
-
-	if err := f.setupCORS(engine); err != nil {
-		log.Fatalf("Failed to setup CORS: %v", err)
+	// gin.Context delegates Deadline, Done and Err to the request context, so
+	// handlers can pass the RequestContext to GORM and Redis directly
+	engine.ContextWithFallback = true
+	engine.Use(requestIDMiddleware())
+	if !f.accessLogConfig.Disabled {
+		engine.Use(f.accessLogMiddleware())
 	}
-
-	return engine
-}
-
-func (f *serverFeature) setupCORS(engine *gin.Engine) error {
-	corsCfg := &config.CORSConfig{}
-	if err := config.ResolveConfig(corsCfg); err != nil {
-		return fmt.Errorf("failed to load CORS config: %w", err)
+	if f.compressionConfig.Enabled {
+		engine.Use(f.compressionMiddleware())
 	}
+	engine.Use(f.recoveryMiddleware())
+	engine.Use(f.abortedErrorMiddleware())
 
-	if len(corsCfg.AllowedOrigins) == 0 && len(corsCfg.AllowedMethods) == 0 && len(corsCfg.AllowedHeaders) == 0 {
-		return nil
+	if err := f.setupTrustedProxies(engine); err != nil {
+		log.Fatalf("Failed to setup trusted proxies: %v", err)
 	}
-
-	if err := corsCfg.Validate(); err != nil {
-		return fmt.Errorf("CORS config validation failed: %w", err)
+	if !f.securityConfig.HeadersDisabled {
+		engine.Use(f.securityHeadersMiddleware())
 	}
-
-	corsConfig := cors.Config{
-		AllowOrigins:     corsCfg.AllowedOrigins,
-		AllowMethods:     corsCfg.AllowedMethods,
-		AllowHeaders:     corsCfg.AllowedHeaders,
-		AllowCredentials: corsCfg.AllowCredentials,
+	if len(f.securityConfig.IPAllowList) > 0 || len(f.securityConfig.IPDenyList) > 0 {
+		ipFilter, err := f.ipFilterMiddleware()
+		if err != nil {
+			log.Fatalf("Failed to setup IP filter: %v", err)
+		}
+		engine.Use(ipFilter)
+	}
+	if err := f.setupCORS(engine); err != nil {
+		log.Fatalf("Failed to setup CORS: %v", err)
 	}
-	engine.Use(cors.New(corsConfig))
 
-	return nil
+	return engine
 }
 
 func (f *serverFeature) setupHealthCheck() {
@@ -252,14 +375,126 @@ func (f *serverFeature) setupHealthCheck() {
 	})
 }
 
-func (f *serverFeature) setupRoutes() {
+func (f *serverFeature) loadOpenAPIConfig() error {
+	cfg := &config.OpenAPIConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load OpenAPI config: %w", err)
+	}
+
+	if cfg.Path == "" {
+		cfg.Path = "/openapi.json"
+	}
+	if cfg.SwaggerUIPath == "" {
+		cfg.SwaggerUIPath = "/docs"
+	}
+	if cfg.Title == "" {
+		cfg.Title = f.Config.Name
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("OpenAPI config validation failed: %w", err)
+	}
+
+	f.openAPIConfig = cfg
+	return nil
+}
+
+func (f *serverFeature) openAPIDocument() *openapi.Document {
+	opts := openapi.Options{
+		Info: openapi.Info{
+			Title:       f.openAPIConfig.Title,
+			Version:     f.Config.Version,
+			Description: f.openAPIConfig.Description,
+		},
+	}
+	for _, url := range f.openAPIConfig.ServerURLs {
+		opts.Servers = append(opts.Servers, openapi.Server{URL: url})
+	}
+
+	return openapi.Generate(f.routes, opts)
+}
+
+func (f *serverFeature) OpenAPI() ([]byte, error) {
+	return f.openAPIDocument().JSON()
+}
+
+func (f *serverFeature) setupOpenAPI() {
+	if !f.openAPIConfig.Enabled {
+		return
+	}
+
+	handler, err := openapi.Handler(f.openAPIDocument())
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
+	f.Engine.GET(f.openAPIConfig.Path, handler)
+
+	if f.openAPIConfig.SwaggerUI {
+		assetsPath := strings.TrimSuffix(f.openAPIConfig.SwaggerUIPath, "/") + "/assets"
+		ui, err := openapi.SwaggerUIHandler(f.openAPIConfig.Title, f.openAPIConfig.Path, assetsPath)
+		if err != nil {
+			log.Fatalf("Failed to build Swagger UI: %v", err)
+		}
+		f.Engine.GET(f.openAPIConfig.SwaggerUIPath, ui)
+		f.Engine.GET(assetsPath+"/*filepath", openapi.SwaggerUIAssetsHandler())
+	}
+}
+
+// setupSignedFiles serves the signed download URLs of the storage feature, when it is added
+func (f *serverFeature) setupSignedFiles() {
+	var storage StorageService
+	if err := f.App.Find(&storage); err != nil {
+		return
+	}
+
+	f.Engine.GET(strings.TrimSuffix(storage.URLPath(), "/")+"/*key", f.createHandler(func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		return serveSignedFile(c, storage)
+	}))
+}
+
+func (f *serverFeature) setupRoutes() error {
 	f.setupHealthCheck()
+	f.setupOpenAPI()
+	f.setupSignedFiles()
+
+	corsRoutes, err := f.setupRouteCORS()
+	if err != nil {
+		return err
+	}
+
+	if err := f.setupNoRoute(); err != nil {
+		return err
+	}
 
 	for _, r := range f.routes {
-		handler := f.createHandler(r.Handler)
+		var handler gin.HandlerFunc
+		if r.Proxy != nil {
+			if handler, err = f.proxyHandler(r); err != nil {
+				return err
+			}
+		} else {
+			handler = f.createHandler(r.Handler)
+		}
 
-		// Combine middlewares with handler
-		handlers := append(r.Middlewares, handler)
+		// Combine middlewares with handler, the timeout covering both
+		var handlers []gin.HandlerFunc
+		if r.CORS != nil {
+			handlers = append(handlers, corsRoutes[r.CORS])
+		}
+		if timeout := f.routeTimeout(r); timeout > 0 {
+			handlers = append(handlers, f.timeoutMiddleware(timeout))
+		}
+		if r.Version != "" || r.Deprecation != nil {
+			handlers = append(handlers, f.routeVersionMiddleware(r))
+		}
+		if body := f.bodyMiddleware(r); body != nil {
+			handlers = append(handlers, body)
+		}
+		handlers = append(handlers, r.Middlewares...)
+		if cache := f.httpCacheMiddleware(r); cache != nil {
+			handlers = append(handlers, cache)
+		}
+		handlers = append(handlers, handler)
 
 		switch r.Method {
 		case "GET":
@@ -272,10 +507,13 @@ func (f *serverFeature) setupRoutes() {
 			f.Engine.DELETE(r.Path, handlers...)
 		case "PATCH":
 			f.Engine.PATCH(r.Path, handlers...)
+		case "HEAD":
+			f.Engine.HEAD(r.Path, handlers...)
 		default:
 			// Skip unknown methods
 		}
 	}
+	return nil
 }
 
 func (f *serverFeature) createHandler(handler contracts.CustomizedHandlerFunc) gin.HandlerFunc {
@@ -290,6 +528,11 @@ func (f *serverFeature) createHandler(handler contracts.CustomizedHandlerFunc) g
 		}
 
 		data, bizErr := handler(reqCtx)
+		// The result of a handler that ran past its deadline is discarded
+		if timedOut(c) && !c.Writer.Written() {
+			f.writeTimeout(c)
+			return
+		}
 		if bizErr != nil {
 			f.handleError(c, bizErr)
 			return
@@ -297,12 +540,35 @@ func (f *serverFeature) createHandler(handler contracts.CustomizedHandlerFunc) g
 		// Check if response has already been written (e.g., by c.Data() or c.String())
 		// If already written, don't serialize the return value to avoid appending null
 		if !c.Writer.Written() {
-			c.JSON(200, data)
+			f.writeResult(reqCtx, data)
 		}
 	}
 }
 
+// abortedErrorMiddleware renders errors of middlewares that abort with
+// contracts.AbortWithError through the error handler
+func (f *serverFeature) abortedErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.IsAborted() && !c.Writer.Written() && len(c.Errors) > 0 {
+			f.handleError(c, c.Errors.Last().Err)
+		}
+	}
+}
+
+// handleError renders err with the error handler. The details of server
+// errors, their cause and stack trace, are logged and never rendered.
 func (f *serverFeature) handleError(c *gin.Context, err error) {
+	c.Set(contextKeyError, err)
+
+	var bizErr bizerr.BizError
+	if !errors.As(err, &bizErr) {
+		logger.ErrorContext(c.Request.Context(), "%s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+	} else if bizErr.HTTPCode() >= http.StatusInternalServerError && bizErr.Unwrap() != nil {
+		logger.ErrorContext(c.Request.Context(), "%s %s failed: %+v", c.Request.Method, c.Request.URL.Path, bizErr)
+	}
+
 	if f.errorHandler != nil {
 		f.errorHandler.HandleError(c, err)
 		return
@@ -311,16 +577,36 @@ func (f *serverFeature) handleError(c *gin.Context, err error) {
 }
 
 func (f *serverFeature) defaultHandleError(c *gin.Context, err error) {
-	bizErr, ok := err.(bizerr.BizError)
-	if ok {
-		c.JSON(bizErr.HTTPCode(), gin.H{
-			"message": bizErr.Message(),
-		})
+	requestID := logger.RequestID(c.Request.Context())
+
+	var bizErr bizerr.BizError
+	if errors.As(err, &bizErr) {
+		body := gin.H{
+			"code":       bizErr.Code(),
+			"message":    f.errorMessage(c, bizErr),
+			"request_id": requestID,
+		}
+		if bizErr.IsValidationError() && len(bizErr.ValidationErrors()) > 0 {
+			body["fields"] = bizErr.ValidationErrors()
+		}
+		f.render(c, bizErr.HTTPCode(), body)
 		return
 	}
 
-	systemErr := bizerr.New(http.StatusInternalServerError, err)
-	c.JSON(systemErr.HTTPCode(), gin.H{
-		"message": systemErr.Message(),
+	systemErr := bizerr.ErrInternalServerError(err)
+	f.render(c, systemErr.HTTPCode(), gin.H{
+		"code":       systemErr.Code(),
+		"message":    systemErr.Message(),
+		"request_id": requestID,
 	})
 }
+
+// errorMessage translates the message of errors created from a catalogue
+// definition into the request language, when the i18n feature is added
+func (f *serverFeature) errorMessage(c *gin.Context, err bizerr.BizError) string {
+	var translator contracts.Translator
+	f.App.Find(&translator)
+
+	reqCtx := &contracts.RequestContext{Context: c, App: f.App, Translator: translator}
+	return reqCtx.ErrorMessage(err)
+}
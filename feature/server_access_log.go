@@ -0,0 +1,174 @@
+package feature
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// contextKeyError is the gin.Context key of the error rendered for the request
+const contextKeyError = "aurora_error"
+
+func (f *serverFeature) loadAccessLogConfig() error {
+	cfg := &config.AccessLogConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load access log config: %w", err)
+	}
+
+	if cfg.Format == "" {
+		cfg.Format = config.AccessLogJSON
+		if f.Config.RunLevel == config.RunLevelLocal {
+			cfg.Format = config.AccessLogText
+		}
+	}
+	if cfg.SampleRate == nil {
+		sampleRate := 1.0
+		cfg.SampleRate = &sampleRate
+	}
+	if len(cfg.SkipPaths) == 0 {
+		cfg.SkipPaths = []string{"/health", "/ready"}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("access log config validation failed: %w", err)
+	}
+
+	f.accessLogConfig = cfg
+	return nil
+}
+
+// accessRecord is an access log record, its fields in output order
+type accessRecord struct {
+	Time      string  `json:"time"`
+	Level     string  `json:"level"`
+	Method    string  `json:"method"`
+	Route     string  `json:"route,omitempty"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Bytes     int     `json:"bytes"`
+	ClientIP  string  `json:"client_ip"`
+	UserID    string  `json:"user_id,omitempty"`
+	RequestID string  `json:"request_id,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	Slow      bool    `json:"slow,omitempty"`
+}
+
+// accessLogMiddleware writes an access log record per request through logger.Access
+func (f *serverFeature) accessLogMiddleware() gin.HandlerFunc {
+	cfg := f.accessLogConfig
+	sampleRate := *cfg.SampleRate
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		slow := cfg.SlowThreshold > 0 && latency >= cfg.SlowThreshold
+		if status < 500 && !slow {
+			if skip[path] || (sampleRate < 1 && rand.Float64() >= sampleRate) {
+				return
+			}
+		}
+
+		record := &accessRecord{
+			Time:      start.Format(time.RFC3339Nano),
+			Level:     "info",
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			Path:      path,
+			Status:    status,
+			LatencyMS: float64(latency.Microseconds()) / 1000,
+			Bytes:     max(c.Writer.Size(), 0),
+			ClientIP:  c.ClientIP(),
+			RequestID: logger.RequestID(c.Request.Context()),
+			Slow:      slow,
+		}
+		if userID, ok := c.Get(ContextKeyUserID); ok {
+			record.UserID = fmt.Sprint(userID)
+		}
+		if err, ok := c.Get(contextKeyError); ok {
+			if err, ok := err.(error); ok {
+				record.Error = err.Error()
+			}
+		}
+		switch {
+		case status >= 500:
+			record.Level = "error"
+		case slow:
+			record.Level = "warn"
+		}
+
+		logger.Access(formatAccessRecord(cfg.Format, record))
+	}
+}
+
+func formatAccessRecord(format string, r *accessRecord) string {
+	switch format {
+	case config.AccessLogJSON:
+		data, _ := json.Marshal(r)
+		return string(data)
+	case config.AccessLogLogfmt:
+		fields := []string{
+			"time=" + r.Time,
+			"level=" + r.Level,
+			"method=" + r.Method,
+		}
+		if r.Route != "" {
+			fields = append(fields, "route="+logfmtValue(r.Route))
+		}
+		fields = append(fields,
+			"path="+logfmtValue(r.Path),
+			"status="+strconv.Itoa(r.Status),
+			"latency_ms="+strconv.FormatFloat(r.LatencyMS, 'f', -1, 64),
+			"bytes="+strconv.Itoa(r.Bytes),
+			"client_ip="+logfmtValue(r.ClientIP),
+		)
+		for _, field := range [][2]string{{"user_id", r.UserID}, {"request_id", r.RequestID}, {"error", r.Error}} {
+			if field[1] != "" {
+				fields = append(fields, field[0]+"="+logfmtValue(field[1]))
+			}
+		}
+		if r.Slow {
+			fields = append(fields, "slow=true")
+		}
+		return strings.Join(fields, " ")
+	default:
+		line := fmt.Sprintf("[ACCESS] %s | %3d | %10.3fms | %15s | %-7s %q",
+			r.Time, r.Status, r.LatencyMS, r.ClientIP, r.Method, r.Path)
+		for _, field := range [][2]string{{"user_id", r.UserID}, {"request_id", r.RequestID}} {
+			if field[1] != "" {
+				line += " | " + field[0] + "=" + field[1]
+			}
+		}
+		if r.Slow {
+			line += " | slow"
+		}
+		if r.Error != "" {
+			line += " | error=" + strconv.Quote(r.Error)
+		}
+		return line
+	}
+}
+
+// logfmtValue quotes values that are empty or contain spaces, quotes or equal signs
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t\n") {
+		return strconv.Quote(value)
+	}
+	return value
+}
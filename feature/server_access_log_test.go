@@ -0,0 +1,81 @@
+package feature
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// captureAccessLog returns the buffer the access log is written to during the test
+func captureAccessLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	logger.SetAccessOutput(&buf)
+	t.Cleanup(func() { logger.SetAccessOutput(os.Stdout) })
+	return &buf
+}
+
+func newAccessLogServer(t *testing.T) *serverFeature {
+	t.Setenv("ACCESS_LOG_DISABLED", "false")
+	t.Setenv("ACCESS_LOG_FORMAT", "json")
+	return newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/users/:id", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"id": c.Param("id")}, nil
+		}},
+		{Method: "GET", Path: "/fail", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return nil, bizerr.ErrInternalServerError(errors.New("db down"))
+		}},
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	buf := captureAccessLog(t)
+	f := newAccessLogServer(t)
+
+	serve(f.Engine, httptest.NewRequest("GET", "/users/7", nil))
+	serve(f.Engine, httptest.NewRequest("GET", "/health", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("records = %q, want only /users/7", lines)
+	}
+	var record accessRecord
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Route != "/users/:id" || record.Path != "/users/7" || record.Status != 200 || record.Level != "info" || record.RequestID == "" {
+		t.Errorf("record = %+v", record)
+	}
+}
+
+func TestAccessLogSampleRateZero(t *testing.T) {
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "0")
+	buf := captureAccessLog(t)
+	f := newAccessLogServer(t)
+
+	for i := 0; i < 10; i++ {
+		serve(f.Engine, httptest.NewRequest("GET", "/users/7", nil))
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("sampled out requests were logged: %s", buf.String())
+	}
+
+	// Server errors are logged whatever the sample rate
+	serve(f.Engine, httptest.NewRequest("GET", "/fail", nil))
+	var record accessRecord
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != 500 || record.Level != "error" || record.Error == "" {
+		t.Errorf("record = %+v", record)
+	}
+}
@@ -0,0 +1,51 @@
+package feature
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// routeBodyLimit returns the body size limit of r, falling back to MAX_BODY_SIZE; zero means none
+func (f *serverFeature) routeBodyLimit(r contracts.Route) int64 {
+	if r.MaxBodySize < 0 {
+		return 0
+	}
+	if r.MaxBodySize > 0 {
+		return r.MaxBodySize
+	}
+	return f.Config.MaxBodySize
+}
+
+// bodyMiddleware limits the request body of r and configures DecodeJSON.
+// It returns nil when there is nothing to do for r.
+func (f *serverFeature) bodyMiddleware(r contracts.Route) gin.HandlerFunc {
+	limit := f.routeBodyLimit(r)
+	strict := f.Config.StrictJSON || r.StrictJSON
+	maxDepth := f.Config.JSONMaxDepth
+	if limit == 0 && !strict && maxDepth == 0 {
+		return nil
+	}
+
+	return func(c *gin.Context) {
+		if strict {
+			c.Set(contracts.ContextKeyStrictJSON, true)
+		}
+		if maxDepth > 0 {
+			c.Set(contracts.ContextKeyJSONMaxDepth, maxDepth)
+		}
+		if limit > 0 && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			// Reject declared sizes upfront; chunked bodies fail when the limit is read past
+			if c.Request.ContentLength > limit {
+				contracts.AbortWithError(c, bizerr.ErrRequestEntityTooLarge(fmt.Errorf("request body exceeds %d bytes", limit)))
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
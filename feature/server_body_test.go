@@ -0,0 +1,162 @@
+package feature
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+type createItemReq struct {
+	Name  string `json:"name" binding:"required"`
+	Count int    `json:"count"`
+	Tags  interface{}
+}
+
+// chunked returns a request whose body has no declared length
+func chunked(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, io.NopCloser(strings.NewReader(body)))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestBodyLimit(t *testing.T) {
+	t.Setenv("MAX_BODY_SIZE", "64")
+	t.Setenv("RATE_LIMIT_STORE", "memory")
+
+	a := newTestApp(t)
+	if err := a.ProvideAs(newMemoryRedis(), (*RedisService)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	decode := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		var req createItemReq
+		if err := c.DecodeJSON(&req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+	f := newTestServer(t, a, []contracts.Route{
+		{Method: "POST", Path: "/items", Handler: decode},
+		{Method: "POST", Path: "/uploads", MaxBodySize: -1, Handler: decode},
+		{Method: "POST", Path: "/orders", Handler: decode, Middlewares: []gin.HandlerFunc{IdempotencyMiddleware(a, IdempotencyOptions{})}},
+	})
+
+	large := `{"name":"` + strings.Repeat("x", 100) + `"}`
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"declared length", httptest.NewRequest("POST", "/items", strings.NewReader(large)), http.StatusRequestEntityTooLarge},
+		{"chunked", chunked("POST", "/items", large), http.StatusRequestEntityTooLarge},
+		{"within limit", chunked("POST", "/items", `{"name":"book"}`), http.StatusOK},
+		{"route without limit", chunked("POST", "/uploads", large), http.StatusOK},
+		{"idempotency fingerprint", chunked("POST", "/orders", large), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		if tt.name == "idempotency fingerprint" {
+			tt.req.Header.Set(IdempotencyKeyHeader, "key-1")
+		}
+		w := serve(f.Engine, tt.req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		if tt.status == http.StatusRequestEntityTooLarge {
+			if body := decodeBody(t, w); body["code"] != bizerr.CodeRequestEntityTooLarge.Code {
+				t.Errorf("%s: body = %v", tt.name, body)
+			}
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Setenv("STRICT_JSON", "true")
+	t.Setenv("JSON_MAX_DEPTH", "3")
+
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "POST", Path: "/decode", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			var req createItemReq
+			if err := c.DecodeJSON(&req); err != nil {
+				return nil, err
+			}
+			return req, nil
+		}},
+		// gin's binding is not changed by the server configuration
+		{Method: "POST", Path: "/bind", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			var req createItemReq
+			if err := c.ShouldBindJSON(&req); err != nil {
+				return nil, bizerr.ErrBadRequest(err)
+			}
+			return req, nil
+		}},
+	})
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		fields map[string]interface{}
+	}{
+		{"/decode", `{"name":"book","count":2}`, http.StatusOK, nil},
+		{"/decode", `{"count":2}`, http.StatusBadRequest, map[string]interface{}{"name": "failed on the 'required' rule"}},
+		{"/decode", `{"name":"book","count":"two"}`, http.StatusBadRequest, map[string]interface{}{"count": "must be of type int"}},
+		{"/decode", `{"name":"book","color":"red"}`, http.StatusBadRequest, map[string]interface{}{"color": "unknown field"}},
+		{"/decode", `{"name":"book"} {}`, http.StatusBadRequest, nil},
+		{"/decode", `{"name":"book","Tags":[[[1]]]}`, http.StatusBadRequest, nil},
+		{"/decode", `{"name":"book","Tags":[[1]]}`, http.StatusOK, nil},
+		{"/bind", `{"name":"book","Tags":[[[1]]]}`, http.StatusOK, nil},
+		{"/bind", `{"name":"book","color":"red"}`, http.StatusOK, nil},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := serve(f.Engine, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %s: status = %d, want %d: %s", tt.path, tt.body, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		if tt.fields != nil {
+			body := decodeBody(t, w)
+			fields, _ := body["fields"].(map[string]interface{})
+			for name, message := range tt.fields {
+				if fields[name] != message {
+					t.Errorf("%s %s: fields = %v", tt.path, tt.body, body["fields"])
+				}
+			}
+		}
+	}
+}
+
+func TestRouteStrictJSON(t *testing.T) {
+	decode := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		var req createItemReq
+		if err := c.DecodeJSON(&req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "POST", Path: "/strict", StrictJSON: true, Handler: decode},
+		{Method: "POST", Path: "/lenient", Handler: decode},
+	})
+
+	for path, status := range map[string]int{"/strict": http.StatusBadRequest, "/lenient": http.StatusOK} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"name":"book","color":"red"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if w := serve(f.Engine, req); w.Code != status {
+			t.Errorf("%s: status = %d, want %d: %s", path, w.Code, status, w.Body.String())
+		}
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		t.Error("strict JSON leaked into gin's binding")
+	}
+}
@@ -0,0 +1,378 @@
+package feature
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// CacheStatusHeader tells whether a response was served from the Redis response cache
+const CacheStatusHeader = "X-Cache"
+
+func (f *serverFeature) loadHTTPCacheConfig() error {
+	cfg := &config.HTTPCacheConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load HTTP cache config: %w", err)
+	}
+
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = "httpcache"
+	}
+	if cfg.MaxBodySize == 0 {
+		cfg.MaxBodySize = 1 << 20
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("HTTP cache config validation failed: %w", err)
+	}
+
+	f.httpCacheConfig = cfg
+	f.responseCache = &responseCache{app: f.App, config: cfg}
+	return nil
+}
+
+// cachedResponse is a response stored in the Redis response cache
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// responseCache stores responses in Redis, indexing their keys by tag in hashes
+type responseCache struct {
+	app    contracts.App
+	config *config.HTTPCacheConfig
+}
+
+func (rc *responseCache) redis() (RedisService, error) {
+	var redis RedisService
+	if err := rc.app.Find(&redis); err != nil {
+		return nil, errors.New("the response cache requires the redis feature")
+	}
+	return redis, nil
+}
+
+// Invalidate removes the cached responses carrying any of tags
+func (rc *responseCache) Invalidate(ctx context.Context, tags ...string) error {
+	redis, err := rc.redis()
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		tagKey := rc.tagKey(tag)
+		keys, err := redis.HKeys(ctx, tagKey)
+		if err != nil {
+			return fmt.Errorf("failed to invalidate cache tag %s: %w", tag, err)
+		}
+		if _, err := redis.Delete(ctx, append(keys, tagKey)...); err != nil {
+			return fmt.Errorf("failed to invalidate cache tag %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+func (rc *responseCache) tagKey(tag string) string {
+	return rc.config.KeyPrefix + ":tag:" + tag
+}
+
+// key identifies the response of c by route, path and query parameters, user
+// and the request headers the response varies on
+func (rc *responseCache) key(c *gin.Context, policy *contracts.CachePolicy) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.URL.Path + "?" + c.Request.URL.Query().Encode() + "\n"))
+	if userID, ok := c.Get(ContextKeyUserID); ok {
+		h.Write([]byte(fmt.Sprintf("user:%v\n", userID)))
+	}
+	for _, name := range cacheVary(policy) {
+		h.Write([]byte(name + ": " + c.GetHeader(name) + "\n"))
+	}
+	return rc.config.KeyPrefix + ":" + c.Request.Method + ":" + c.FullPath() + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (rc *responseCache) load(ctx context.Context, redis RedisService, key string) (*cachedResponse, error) {
+	exists, err := redis.Exists(ctx, key)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := redis.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	entry := &cachedResponse{}
+	if err := json.Unmarshal([]byte(data), entry); err != nil {
+		return nil, fmt.Errorf("invalid cached response %s: %w", key, err)
+	}
+	return entry, nil
+}
+
+// store saves entry under key, registering it first under its tags so that an
+// invalidation cannot miss it
+func (rc *responseCache) store(ctx context.Context, redis RedisService, key string, entry *cachedResponse, tags []string, ttl time.Duration) error {
+	for _, tag := range tags {
+		tagKey := rc.tagKey(tag)
+		if err := redis.HSet(ctx, tagKey, key, "1"); err != nil {
+			return err
+		}
+		if err := redis.Expire(ctx, tagKey, ttl); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return redis.Set(ctx, key, string(data), ttl)
+}
+
+func cacheVary(policy *contracts.CachePolicy) []string {
+	return append([]string{"Accept", "Accept-Language"}, policy.Vary...)
+}
+
+// httpCacheMiddleware adds the ETag and Cache-Control headers to the responses
+// of GET routes, answers conditional requests with 304 and serves routes with
+// a SharedTTL from the Redis response cache. It returns nil when there is
+// nothing to do for r; proxy routes only get it with a Cache policy.
+func (f *serverFeature) httpCacheMiddleware(r contracts.Route) gin.HandlerFunc {
+	policy := r.Cache
+	if r.Method != http.MethodGet || (policy == nil && (f.httpCacheConfig.ETagDisabled || r.Proxy != nil)) {
+		return nil
+	}
+	shared := policy != nil && policy.SharedTTL > 0
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var redis RedisService
+		var key string
+		if shared {
+			var err error
+			if redis, err = f.responseCache.redis(); err != nil {
+				logger.ErrorContext(ctx, "%v", err)
+			} else {
+				key = f.responseCache.key(c, policy)
+				entry, err := f.responseCache.load(ctx, redis, key)
+				if err != nil {
+					logger.ErrorContext(ctx, "response cache lookup failed: %v", err)
+				}
+				if entry != nil {
+					header := c.Writer.Header()
+					for name, values := range entry.Header {
+						header[name] = values
+					}
+					header.Set(CacheStatusHeader, "HIT")
+					f.writeConditional(c, c.Writer, entry.Status, entry.Body)
+					c.Abort()
+					return
+				}
+			}
+		}
+
+		before := c.Writer.Header().Clone()
+		w := &cacheWriter{ResponseWriter: c.Writer, maxSize: f.httpCacheConfig.MaxBodySize, status: http.StatusOK}
+		c.Writer = w
+		defer func() { c.Writer = w.ResponseWriter }()
+		c.Next()
+
+		if w.passthrough {
+			return
+		}
+		if !w.wroteHeader && w.buf.Len() == 0 {
+			return
+		}
+
+		header := w.Header()
+		if w.status == http.StatusOK {
+			if policy != nil {
+				if header.Get("Cache-Control") == "" {
+					header.Set("Cache-Control", policy.CacheControl())
+				}
+				// The headers the cache key depends on, so browsers and proxies
+				// keep the negotiated and translated variants apart
+				addVary(header, cacheVary(policy)...)
+			}
+			if !f.httpCacheConfig.ETagDisabled && header.Get("ETag") == "" {
+				sum := sha256.Sum256(w.buf.Bytes())
+				header.Set("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+			}
+
+			if key != "" && !policy.NoStore && header.Get("Set-Cookie") == "" {
+				entry := &cachedResponse{Status: w.status, Header: addedHeaders(before, header), Body: w.buf.Bytes()}
+				tags := policy.Tags
+				if extra, ok := c.Get(contracts.ContextKeyCacheTags); ok {
+					tags = append(append([]string{}, tags...), extra.([]string)...)
+				}
+				if err := f.responseCache.store(ctx, redis, key, entry, tags, policy.SharedTTL); err != nil {
+					logger.ErrorContext(ctx, "response cache store failed: %v", err)
+				}
+				header.Set(CacheStatusHeader, "MISS")
+			}
+		}
+
+		f.writeConditional(c, w.ResponseWriter, w.status, w.buf.Bytes())
+	}
+}
+
+// addedHeaders returns the headers of after that are not in before, i.e. the
+// ones set by the handler rather than by the middlewares before it
+func addedHeaders(before, after http.Header) http.Header {
+	added := make(http.Header)
+	for name, values := range after {
+		if strings.Join(before[name], ",") != strings.Join(values, ",") {
+			added[name] = values
+		}
+	}
+	return added
+}
+
+// writeConditional writes a response, or 304 when it is a successful response
+// still fresh for the client per If-None-Match or If-Modified-Since
+func (f *serverFeature) writeConditional(c *gin.Context, w gin.ResponseWriter, status int, body []byte) {
+	if status == http.StatusOK && notModified(c.Request, w.Header()) {
+		header := w.Header()
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		w.WriteHeaderNow()
+		return
+	}
+
+	w.WriteHeader(status)
+	if len(body) == 0 {
+		w.WriteHeaderNow()
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// notModified evaluates the conditional headers of req against the response
+// header; If-Modified-Since is ignored when If-None-Match is present
+func notModified(req *http.Request, header http.Header) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		etag := header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(ims)
+}
+
+// cacheWriter buffers the response of the handler so that its ETag can be
+// computed. Flushed (streamed) responses and responses larger than maxSize
+// are passed through.
+type cacheWriter struct {
+	gin.ResponseWriter
+	maxSize     int
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+	passthrough bool
+}
+
+func (w *cacheWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *cacheWriter) WriteHeader(code int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *cacheWriter) WriteHeaderNow() {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.wroteHeader = true
+}
+
+func (w *cacheWriter) Status() int {
+	if w.passthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *cacheWriter) Written() bool {
+	return w.wroteHeader || w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *cacheWriter) Write(data []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	w.wroteHeader = true
+	if w.buf.Len()+len(data) > w.maxSize {
+		if err := w.pass(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+// Flush passes streamed responses through
+func (w *cacheWriter) Flush() {
+	_ = w.pass()
+	w.ResponseWriter.Flush()
+}
+
+// pass writes out the header and the buffered data, and passes the rest of the response through
+func (w *cacheWriter) pass() error {
+	if w.passthrough {
+		return nil
+	}
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *cacheWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.passthrough = true
+	return w.ResponseWriter.Hijack()
+}
@@ -0,0 +1,185 @@
+package feature
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// cacheRedis adds the hash operations of the response cache tags to memoryRedis
+type cacheRedis struct {
+	*memoryRedis
+	hashes map[string]map[string]string
+}
+
+func (r *cacheRedis) HSet(ctx context.Context, key, field string, value interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hashes[key] == nil {
+		r.hashes[key] = make(map[string]string)
+	}
+	r.hashes[key][field] = "1"
+	return nil
+}
+
+func (r *cacheRedis) HKeys(ctx context.Context, key string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var fields []string
+	for field := range r.hashes[key] {
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (r *cacheRedis) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return nil
+}
+
+func (r *cacheRedis) Delete(ctx context.Context, keys ...string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.data, key)
+		delete(r.hashes, key)
+	}
+	return int64(len(keys)), nil
+}
+
+func conditionalGet(path, header, value string) *http.Request {
+	req := httptest.NewRequest("GET", path, nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	return req
+}
+
+func TestETag(t *testing.T) {
+	modified := time.Date(2024, 12, 8, 14, 30, 0, 0, time.UTC)
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/customer", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"id": 42}, nil
+		}},
+		{Method: "GET", Path: "/report", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			c.SetLastModified(modified)
+			return gin.H{"total": 7}, nil
+		}},
+		{Method: "GET", Path: "/missing", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return nil, bizerr.ErrNotFound()
+		}},
+		{Method: "POST", Path: "/customer", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"id": 42}, nil
+		}},
+	})
+
+	w := serve(f.Engine, conditionalGet("/customer", "", ""))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag = %q", w.Code, etag)
+	}
+	if again := serve(f.Engine, conditionalGet("/customer", "", "")); again.Header().Get("ETag") != etag {
+		t.Errorf("ETag changed for the same body: %q, %q", etag, again.Header().Get("ETag"))
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"matching ETag", conditionalGet("/customer", "If-None-Match", etag), http.StatusNotModified},
+		{"strong form of the ETag", conditionalGet("/customer", "If-None-Match", etag[2:]), http.StatusNotModified},
+		{"ETag list", conditionalGet("/customer", "If-None-Match", `"other", `+etag), http.StatusNotModified},
+		{"other ETag", conditionalGet("/customer", "If-None-Match", `"other"`), http.StatusOK},
+		{"not modified since", conditionalGet("/report", "If-Modified-Since", modified.Format(http.TimeFormat)), http.StatusNotModified},
+		{"modified since", conditionalGet("/report", "If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat)), http.StatusOK},
+		{"error", conditionalGet("/missing", "If-None-Match", "*"), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := serve(f.Engine, tt.req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+			continue
+		}
+		if tt.status == http.StatusNotModified && (w.Body.Len() != 0 || w.Header().Get("Content-Type") != "") {
+			t.Errorf("%s: 304 has a body %q or Content-Type %q", tt.name, w.Body.String(), w.Header().Get("Content-Type"))
+		}
+	}
+
+	if w := serve(f.Engine, httptest.NewRequest("POST", "/customer", nil)); w.Header().Get("ETag") != "" {
+		t.Errorf("POST response has an ETag")
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	a := newTestApp(t)
+	if err := a.ProvideAs(&cacheRedis{memoryRedis: newMemoryRedis(), hashes: make(map[string]map[string]string)}, (*RedisService)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	f := newTestServer(t, a, []contracts.Route{
+		{
+			Method: "GET",
+			Path:   "/customer/:id",
+			Cache:  &contracts.CachePolicy{MaxAge: time.Minute, Private: true, SharedTTL: time.Minute, Tags: []string{"customers"}, Vary: []string{"X-Tenant"}},
+			Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+				calls++
+				c.CacheTags("customer:" + c.Param("id"))
+				return gin.H{"id": c.Param("id"), "calls": calls}, nil
+			},
+		},
+	})
+	var cache contracts.ResponseCache
+	if err := a.Find(&cache); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := serve(f.Engine, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", path, w.Code)
+		}
+		return w
+	}
+
+	vary := strings.Join([]string{"Accept", "Accept-Language", "X-Tenant"}, ",")
+	w := get("/customer/42")
+	if w.Header().Get(CacheStatusHeader) != "MISS" || w.Header().Get("Cache-Control") != "private, max-age=60" || strings.Join(w.Header().Values("Vary"), ",") != vary {
+		t.Errorf("first response headers = %v", w.Header())
+	}
+	w = get("/customer/42")
+	if w.Header().Get(CacheStatusHeader) != "HIT" || decodeBody(t, w)["calls"] != float64(1) || w.Header().Get("Cache-Control") != "private, max-age=60" ||
+		strings.Join(w.Header().Values("Vary"), ",") != vary {
+		t.Errorf("cached response = %v %s", w.Header(), w.Body.String())
+	}
+	if get("/customer/7").Header().Get(CacheStatusHeader) != "MISS" {
+		t.Error("other path served from the cache")
+	}
+
+	// The tag added by the handler invalidates only its responses
+	if err := cache.Invalidate(context.Background(), "customer:42"); err != nil {
+		t.Fatal(err)
+	}
+	if w := get("/customer/42"); w.Header().Get(CacheStatusHeader) != "MISS" {
+		t.Errorf("invalidated response served from the cache")
+	}
+	if w := get("/customer/7"); w.Header().Get(CacheStatusHeader) != "HIT" {
+		t.Errorf("response of another tag was invalidated")
+	}
+
+	// The tags of the policy invalidate all of them
+	if err := cache.Invalidate(context.Background(), "customers"); err != nil {
+		t.Fatal(err)
+	}
+	if w := get("/customer/7"); w.Header().Get(CacheStatusHeader) != "MISS" {
+		t.Errorf("response served from the cache after invalidating the route tag")
+	}
+}
@@ -0,0 +1,370 @@
+package feature
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/config"
+)
+
+// CompressionEncoder returns a writer compressing into w. level is 1 to 9,
+// or zero for the encoder default.
+type CompressionEncoder func(w io.Writer, level int) (io.WriteCloser, error)
+
+// WithCompressionEncoder registers an encoder for a Content-Encoding, e.g.
+// "zstd", or replaces a built-in one. It is used when listed in COMPRESSION_ENCODINGS.
+func WithCompressionEncoder(encoding string, encoder CompressionEncoder) ServerOption {
+	return func(f *serverFeature) {
+		if f.encoders == nil {
+			f.encoders = make(map[string]CompressionEncoder)
+		}
+		f.encoders[encoding] = encoder
+	}
+}
+
+var (
+	gzipWriterPools   sync.Map // level -> *sync.Pool
+	zlibWriterPools   sync.Map
+	brotliWriterPools sync.Map
+)
+
+// GzipEncoder compresses with gzip, reusing writers
+func GzipEncoder(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	pool, _ := gzipWriterPools.LoadOrStore(level, &sync.Pool{})
+	if gz, ok := pool.(*sync.Pool).Get().(*gzip.Writer); ok {
+		gz.Reset(w)
+		return &pooledWriter{WriteCloser: gz, pool: pool.(*sync.Pool)}, nil
+	}
+	gz, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledWriter{WriteCloser: gz, pool: pool.(*sync.Pool)}, nil
+}
+
+// DeflateEncoder compresses with the zlib format, which HTTP names "deflate"
+func DeflateEncoder(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = zlib.DefaultCompression
+	}
+	pool, _ := zlibWriterPools.LoadOrStore(level, &sync.Pool{})
+	if zw, ok := pool.(*sync.Pool).Get().(*zlib.Writer); ok {
+		zw.Reset(w)
+		return &pooledWriter{WriteCloser: zw, pool: pool.(*sync.Pool)}, nil
+	}
+	zw, err := zlib.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledWriter{WriteCloser: zw, pool: pool.(*sync.Pool)}, nil
+}
+
+// BrotliEncoder compresses with Brotli, reusing writers. Levels 1 to 9 are
+// Brotli qualities; zero uses the Brotli default.
+func BrotliEncoder(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = brotli.DefaultCompression
+	}
+	pool, _ := brotliWriterPools.LoadOrStore(level, &sync.Pool{})
+	if bw, ok := pool.(*sync.Pool).Get().(*brotli.Writer); ok {
+		bw.Reset(w)
+		return &pooledWriter{WriteCloser: bw, pool: pool.(*sync.Pool)}, nil
+	}
+	return &pooledWriter{WriteCloser: brotli.NewWriterLevel(w, level), pool: pool.(*sync.Pool)}, nil
+}
+
+// pooledWriter returns its compressor to the pool once closed
+type pooledWriter struct {
+	io.WriteCloser
+	pool *sync.Pool
+}
+
+func (w *pooledWriter) Flush() error {
+	if flusher, ok := w.WriteCloser.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+func (w *pooledWriter) Close() error {
+	err := w.WriteCloser.Close()
+	w.pool.Put(w.WriteCloser)
+	return err
+}
+
+func (f *serverFeature) loadCompressionConfig() error {
+	cfg := &config.CompressionConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load compression config: %w", err)
+	}
+
+	if len(cfg.Encodings) == 0 {
+		cfg.Encodings = []string{config.CompressionBrotli, config.CompressionGzip, config.CompressionDeflate}
+	}
+	if cfg.MinSize == 0 {
+		cfg.MinSize = 1024
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = config.DefaultCompressionContentTypes
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("compression config validation failed: %w", err)
+	}
+
+	if f.encoders == nil {
+		f.encoders = make(map[string]CompressionEncoder)
+	}
+	if f.encoders[config.CompressionGzip] == nil {
+		f.encoders[config.CompressionGzip] = GzipEncoder
+	}
+	if f.encoders[config.CompressionDeflate] == nil {
+		f.encoders[config.CompressionDeflate] = DeflateEncoder
+	}
+	if f.encoders[config.CompressionBrotli] == nil {
+		f.encoders[config.CompressionBrotli] = BrotliEncoder
+	}
+	for _, encoding := range cfg.Encodings {
+		if f.encoders[encoding] == nil {
+			return fmt.Errorf("COMPRESSION_ENCODINGS lists %q, which has no encoder; register one with WithCompressionEncoder", encoding)
+		}
+	}
+
+	f.compressionConfig = cfg
+	return nil
+}
+
+// compressionMiddleware compresses responses with the encoding negotiated
+// from Accept-Encoding. The body is buffered up to COMPRESSION_MIN_SIZE to
+// decide; smaller bodies, other content types, event streams, partial content
+// and responses that already set Content-Encoding are sent as they are.
+func (f *serverFeature) compressionMiddleware() gin.HandlerFunc {
+	cfg := f.compressionConfig
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"), cfg.Encodings)
+		if encoding == "" || c.Request.Method == http.MethodHead || c.GetHeader("Range") != "" || c.GetHeader("Upgrade") != "" {
+			c.Writer.Header().Add("Vary", "Accept-Encoding")
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{
+			ResponseWriter: c.Writer,
+			config:         cfg,
+			encoding:       encoding,
+			encoder:        f.encoders[encoding],
+			status:         c.Writer.Status(),
+		}
+		c.Writer = w
+		defer func() {
+			w.finish()
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the encoding with the highest quality in the
+// Accept-Encoding header, preferring the order of encodings on ties, or ""
+// when none is accepted
+func negotiateEncoding(header string, encodings []string) string {
+	if header == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range encodings {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of the body until it can decide whether
+// to compress, then either streams through the encoder or passes through.
+type compressWriter struct {
+	gin.ResponseWriter
+	config   *config.CompressionConfig
+	encoding string
+	encoder  CompressionEncoder
+
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+	decided     bool
+	compressor  io.WriteCloser
+	err         error
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.wroteHeader = true
+		w.decide(false)
+	}
+}
+
+func (w *compressWriter) Status() int {
+	if !w.decided {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *compressWriter) Written() bool {
+	return w.wroteHeader || w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.wroteHeader = true
+		w.buf.Write(data)
+		if w.buf.Len() < w.config.MinSize {
+			return len(data), nil
+		}
+		w.decide(true)
+		return len(data), w.err
+	}
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush decides on the data buffered so far, so that streamed responses
+// are not held back by the minimum size
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(w.buf.Len() > 0)
+	}
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// decide writes the header, compressing when enough data is expected and the
+// response is eligible, then writes out the buffered data
+func (w *compressWriter) decide(large bool) {
+	w.decided = true
+	header := w.Header()
+
+	if large && w.compressible(header) {
+		compressor, err := w.encoder(w.ResponseWriter, w.config.Level)
+		if err == nil {
+			header.Set("Content-Encoding", w.encoding)
+			header.Del("Content-Length")
+			header.Del("Accept-Ranges")
+			w.compressor = compressor
+		}
+	}
+	if w.eligibleType(header) && !containsString(header.Values("Vary"), "Accept-Encoding") {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	if w.compressor != nil {
+		_, w.err = w.compressor.Write(w.buf.Bytes())
+	} else {
+		_, w.err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+}
+
+// finish sends a body shorter than the minimum size and closes the encoder
+func (w *compressWriter) finish() {
+	if !w.decided && w.wroteHeader {
+		w.decide(w.buf.Len() >= w.config.MinSize)
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+		w.compressor = nil
+	}
+}
+
+func (w *compressWriter) compressible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	switch w.status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	return w.eligibleType(header)
+}
+
+func (w *compressWriter) eligibleType(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || mediaType == "text/event-stream" {
+		return false
+	}
+	for _, allowed := range w.config.ContentTypes {
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package feature
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	encodings := []string{"br", "gzip", "deflate"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"gzip, deflate, br", "br"},
+		{"gzip, br;q=0.5", "gzip"},
+		{"deflate", "deflate"},
+		{"*", "br"},
+		{"*;q=0.1, gzip;q=0", "br"},
+		{"br;q=0, gzip;q=0", ""},
+		{"identity", ""},
+		{"GZIP", "gzip"},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header, encodings); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	t.Setenv("COMPRESSION_ENABLED", "true")
+	large := strings.Repeat("aurora ", 1024)
+	text := func(body string) contracts.CustomizedHandlerFunc {
+		return func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			c.String(http.StatusOK, body)
+			return nil, nil
+		}
+	}
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/large", Handler: text(large)},
+		{Method: "GET", Path: "/small", Handler: text("small")},
+		{Method: "GET", Path: "/binary", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			c.Data(http.StatusOK, "image/png", bytes.Repeat([]byte{1}, 4096))
+			return nil, nil
+		}},
+		{Method: "GET", Path: "/json", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"text": large}, nil
+		}},
+	})
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"br":      func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"gzip":    func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"deflate": func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	}
+	for encoding, decode := range decoders {
+		req := httptest.NewRequest("GET", "/large", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		w := serve(f.Engine, req)
+		if w.Header().Get("Content-Encoding") != encoding || w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: headers = %v", encoding, w.Header())
+			continue
+		}
+		r, err := decode(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body, _ := io.ReadAll(r); string(body) != large {
+			t.Errorf("%s: decoded body has %d bytes, want %d", encoding, len(body), len(large))
+		}
+	}
+
+	tests := []struct {
+		path           string
+		acceptEncoding string
+		encoding       string
+		vary           bool
+	}{
+		{"/json", "gzip, deflate, br", "br", true},
+		{"/large", "", "", true},
+		{"/small", "gzip", "", true},
+		{"/binary", "gzip", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+		}
+		w := serve(f.Engine, req)
+		if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%s %q: Content-Encoding = %q, want %q", tt.path, tt.acceptEncoding, got, tt.encoding)
+		}
+		if vary := w.Header().Values("Vary"); (len(vary) == 1 && vary[0] == "Accept-Encoding") != tt.vary {
+			t.Errorf("%s %q: Vary = %v", tt.path, tt.acceptEncoding, vary)
+		}
+	}
+}
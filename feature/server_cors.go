@@ -0,0 +1,161 @@
+package feature
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	defaultCORSHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", contracts.RequestIDHeader}
+)
+
+// setupCORS applies the CORS_* configuration to every route without its own
+// CORS policy. CORS is only enabled when at least one setting is provided.
+func (f *serverFeature) setupCORS(engine *gin.Engine) error {
+	corsCfg := &config.CORSConfig{}
+	if err := config.ResolveConfig(corsCfg); err != nil {
+		return fmt.Errorf("failed to load CORS config: %w", err)
+	}
+
+	if !corsCfg.Enabled() {
+		return nil
+	}
+
+	if err := corsCfg.Validate(); err != nil {
+		return fmt.Errorf("CORS config validation failed: %w", err)
+	}
+
+	handler, err := newCORSMiddleware(&contracts.CORSPolicy{
+		AllowOrigins:        corsCfg.AllowedOrigins,
+		AllowOriginPatterns: corsCfg.AllowedOriginPatterns,
+		AllowMethods:        corsCfg.AllowedMethods,
+		AllowHeaders:        corsCfg.AllowedHeaders,
+		ExposeHeaders:       corsCfg.ExposedHeaders,
+		AllowCredentials:    corsCfg.AllowCredentials,
+		MaxAge:              corsCfg.MaxAge,
+		AllowPrivateNetwork: corsCfg.AllowPrivateNetwork,
+	})
+	if err != nil {
+		return fmt.Errorf("CORS config validation failed: %w", err)
+	}
+
+	engine.Use(func(c *gin.Context) {
+		// Routes with their own policy are handled by their route middleware
+		if f.corsOverrides[c.FullPath()] {
+			c.Next()
+			return
+		}
+		handler(c)
+	})
+	return nil
+}
+
+// setupRouteCORS builds the middlewares of the route CORS policies, and
+// registers OPTIONS routes answering their preflight requests
+func (f *serverFeature) setupRouteCORS() (map[*contracts.CORSPolicy]gin.HandlerFunc, error) {
+	handlers := make(map[*contracts.CORSPolicy]gin.HandlerFunc)
+	paths := make(map[string]*contracts.CORSPolicy)
+	f.corsOverrides = make(map[string]bool)
+
+	for _, r := range f.routes {
+		if r.CORS == nil {
+			continue
+		}
+		if policy, ok := paths[r.Path]; ok {
+			if policy != r.CORS {
+				return nil, fmt.Errorf("routes on %s have different CORS policies", r.Path)
+			}
+			continue
+		}
+		paths[r.Path] = r.CORS
+
+		handler, ok := handlers[r.CORS]
+		if !ok {
+			var err error
+			if handler, err = newCORSMiddleware(r.CORS); err != nil {
+				return nil, fmt.Errorf("invalid CORS policy of %s %s: %w", r.Method, r.Path, err)
+			}
+			handlers[r.CORS] = handler
+		}
+
+		f.corsOverrides[r.Path] = true
+		f.Engine.OPTIONS(r.Path, handler, func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusNoContent)
+		})
+	}
+	return handlers, nil
+}
+
+// newCORSMiddleware builds a gin-contrib/cors middleware from policy,
+// returning configuration errors instead of panicking
+func newCORSMiddleware(policy *contracts.CORSPolicy) (gin.HandlerFunc, error) {
+	if len(policy.AllowOrigins) == 0 && len(policy.AllowOriginPatterns) == 0 && policy.AllowOriginFunc == nil {
+		return nil, fmt.Errorf("no allowed origin")
+	}
+	if err := config.ValidateCORSOrigins(policy.AllowOrigins, policy.AllowOriginPatterns, policy.AllowCredentials); err != nil {
+		return nil, err
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(policy.AllowOriginPatterns))
+	for _, pattern := range policy.AllowOriginPatterns {
+		patterns = append(patterns, regexp.MustCompile("^(?:"+pattern+")$"))
+	}
+
+	cfg := cors.Config{
+		AllowOrigins:        policy.AllowOrigins,
+		AllowMethods:        policy.AllowMethods,
+		AllowHeaders:        policy.AllowHeaders,
+		ExposeHeaders:       policy.ExposeHeaders,
+		AllowCredentials:    policy.AllowCredentials,
+		MaxAge:              policy.MaxAge,
+		AllowPrivateNetwork: policy.AllowPrivateNetwork,
+		AllowWildcard:       true,
+	}
+	if len(cfg.AllowMethods) == 0 {
+		cfg.AllowMethods = defaultCORSMethods
+	}
+	if len(cfg.AllowHeaders) == 0 {
+		cfg.AllowHeaders = defaultCORSHeaders
+	}
+	for _, origin := range policy.AllowOrigins {
+		if origin == "*" {
+			cfg.AllowAllOrigins = true
+			cfg.AllowOrigins = nil
+		}
+	}
+	if !cfg.AllowAllOrigins && (len(patterns) > 0 || policy.AllowOriginFunc != nil) {
+		cfg.AllowOriginFunc = func(origin string) bool {
+			for _, pattern := range patterns {
+				if pattern.MatchString(origin) {
+					return true
+				}
+			}
+			return policy.AllowOriginFunc != nil && policy.AllowOriginFunc(origin)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	handler := cors.New(cfg)
+
+	return func(c *gin.Context) {
+		// The cors middleware replaces the Vary header; keep the values set before it
+		header := c.Writer.Header()
+		vary := header.Values("Vary")
+		handler(c)
+		for _, value := range vary {
+			if !containsString(header.Values("Vary"), value) {
+				header.Add("Vary", value)
+			}
+		}
+	}, nil
+}
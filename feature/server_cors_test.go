@@ -0,0 +1,80 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func preflight(path, origin, method string) *http.Request {
+	req := httptest.NewRequest("OPTIONS", path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	return req
+}
+
+func TestCORS(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com,https://*.example.org")
+	t.Setenv("CORS_ALLOWED_ORIGIN_PATTERNS", `https://[a-z]+\.example\.net`)
+	t.Setenv("CORS_MAX_AGE", "10m")
+
+	ok := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		return gin.H{"ok": true}, nil
+	}
+	partners := &contracts.CORSPolicy{
+		AllowOrigins:     []string{"https://partner.example.com"},
+		AllowMethods:     []string{"GET"},
+		AllowCredentials: true,
+	}
+	routes := []contracts.Route{{Method: "POST", Path: "/orders", Handler: ok}}
+	routes = append(routes, contracts.WithCORS(partners, contracts.Route{Method: "GET", Path: "/partners", Handler: ok})...)
+	f := newTestServer(t, newTestApp(t), routes)
+
+	tests := []struct {
+		name        string
+		req         *http.Request
+		status      int
+		allowOrigin string
+	}{
+		{"preflight of an exact origin", preflight("/orders", "https://app.example.com", "POST"), http.StatusNoContent, "https://app.example.com"},
+		{"preflight of a wildcard origin", preflight("/orders", "https://shop.example.org", "POST"), http.StatusNoContent, "https://shop.example.org"},
+		{"preflight of a pattern origin", preflight("/orders", "https://api.example.net", "POST"), http.StatusNoContent, "https://api.example.net"},
+		{"preflight of another origin", preflight("/orders", "https://evil.example.com", "POST"), http.StatusForbidden, ""},
+		{"route policy preflight", preflight("/partners", "https://partner.example.com", "GET"), http.StatusNoContent, "https://partner.example.com"},
+		{"global origin on a route policy", preflight("/partners", "https://app.example.com", "GET"), http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		w := serve(f.Engine, tt.req)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+			continue
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", tt.name, got, tt.allowOrigin)
+		}
+	}
+
+	w := serve(f.Engine, preflight("/orders", "https://app.example.com", "POST"))
+	if w.Header().Get("Access-Control-Max-Age") != "600" ||
+		!strings.Contains(w.Header().Get("Access-Control-Allow-Headers"), http.CanonicalHeaderKey(contracts.RequestIDHeader)) {
+		t.Errorf("preflight headers = %v", w.Header())
+	}
+	w = serve(f.Engine, preflight("/partners", "https://partner.example.com", "GET"))
+	if w.Header().Get("Access-Control-Allow-Credentials") != "true" || w.Header().Get("Access-Control-Allow-Methods") != "GET" {
+		t.Errorf("route policy preflight headers = %v", w.Header())
+	}
+
+	// Actual requests keep the headers set before the CORS middleware
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w = serve(f.Engine, req)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || w.Header().Get(contracts.RequestIDHeader) == "" {
+		t.Errorf("actual request: %d %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,115 @@
+package feature
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shyandsy/aurora/config"
+)
+
+// systemdListenFDsStart is the first file descriptor passed by systemd socket activation
+const systemdListenFDsStart = 3
+
+// WithListener makes the server serve on ln instead of binding HOST:PORT or LISTEN,
+// e.g. a listener created by a test or a process manager.
+func WithListener(ln net.Listener) ServerOption {
+	return func(f *serverFeature) {
+		f.listener = ln
+	}
+}
+
+// Addr returns the address the server is bound to, or nil before Start.
+// With PORT=0 it carries the port chosen by the system.
+func (f *serverFeature) Addr() net.Addr {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.listener == nil || !f.running {
+		return nil
+	}
+	return f.listener.Addr()
+}
+
+// listen returns the injected listener, or opens the one described by LISTEN,
+// falling back to HOST:PORT
+func (f *serverFeature) listen() (net.Listener, error) {
+	if f.listener != nil {
+		return f.listener, nil
+	}
+	if f.Config.Listen != "" {
+		return listenSpec(f.Config.Listen)
+	}
+	return net.Listen("tcp", net.JoinHostPort(f.Config.Host, strconv.Itoa(f.Config.Port)))
+}
+
+// listenSpec opens a listener from a LISTEN value:
+//
+//	tcp:127.0.0.1:8080  TCP address
+//	unix:/run/app.sock  Unix domain socket, replacing a stale socket file
+//	fd:3                inherited file descriptor
+//	systemd[:name]      socket passed by systemd socket activation
+func listenSpec(spec string) (net.Listener, error) {
+	scheme, value, _ := strings.Cut(spec, ":")
+	switch scheme {
+	case config.ListenTCP:
+		return net.Listen("tcp", value)
+	case config.ListenUnix:
+		if info, err := os.Stat(value); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(value); err != nil {
+				return nil, fmt.Errorf("failed to remove stale socket %s: %w", value, err)
+			}
+		}
+		return net.Listen("unix", value)
+	case config.ListenFD:
+		fd, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid listener file descriptor %q", value)
+		}
+		return fileListener(fd, "fd:"+value)
+	case config.ListenSystemd:
+		return systemdListener(value)
+	default:
+		return nil, fmt.Errorf("unsupported listener %q", spec)
+	}
+}
+
+// systemdListener returns the socket passed by systemd, selected by name from
+// LISTEN_FDNAMES, or the first one when name is empty. The LISTEN_* variables
+// are unset so that child processes do not inherit them.
+func systemdListener(name string) (net.Listener, error) {
+	pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	count, _ := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	if pid != os.Getpid() || count < 1 {
+		return nil, fmt.Errorf("no sockets passed by systemd (LISTEN_PID/LISTEN_FDS not set for this process)")
+	}
+
+	for i := 0; i < count; i++ {
+		if name == "" || (i < len(names) && names[i] == name) {
+			return fileListener(systemdListenFDsStart+i, "systemd:"+name)
+		}
+	}
+	return nil, fmt.Errorf("no socket named %q passed by systemd", name)
+}
+
+// fileListener wraps an inherited file descriptor into a listener
+func fileListener(fd int, name string) (net.Listener, error) {
+	file := os.NewFile(uintptr(fd), name)
+	if file == nil {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer file.Close()
+
+	ln, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("file descriptor %d is not a listening socket: %w", fd, err)
+	}
+	return ln, nil
+}
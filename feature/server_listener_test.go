@@ -0,0 +1,185 @@
+//go:build unix
+
+package feature
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// listenerFD returns a duplicate file descriptor of ln that is owned by the
+// caller, like a socket inherited from a process manager
+func listenerFD(t *testing.T, ln net.Listener) int {
+	t.Helper()
+
+	file, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	fd, err := syscall.Dup(int(file.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestServerAddr(t *testing.T) {
+	a := newTestApp(t)
+	f := NewServerFeature().(*serverFeature)
+	a.AddFeature(f)
+	if addr := f.Addr(); addr != nil {
+		t.Fatalf("Addr before Start = %v, want nil", addr)
+	}
+
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	addr, ok := f.Addr().(*net.TCPAddr)
+	if !ok || addr.Port == 0 || !addr.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Fatalf("Addr with PORT=0 = %v", f.Addr())
+	}
+	resp, err := http.Get("http://" + addr.String() + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Wait()
+	if addr := f.Addr(); addr != nil {
+		t.Errorf("Addr after Close = %v, want nil", addr)
+	}
+}
+
+func TestUnixSocketListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	// A socket file left by a previous process is replaced
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	t.Setenv("LISTEN", "unix:"+path)
+	// Unix socket clients have no IP, so a deny list does not reject them
+	t.Setenv("IP_DENY_LIST", "192.0.2.0/24")
+	a := newTestApp(t)
+	f := NewServerFeature().(*serverFeature)
+	a.AddFeature(f)
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if addr := f.Addr(); addr == nil || addr.Network() != "unix" || addr.String() != path {
+		t.Fatalf("Addr = %v, want unix %s", addr, path)
+	}
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get("http://unix/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestFDListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	inherited, err := listenSpec("fd:" + strconv.Itoa(listenerFD(t, ln)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inherited.Close()
+	if inherited.Addr().String() != ln.Addr().String() {
+		t.Errorf("Addr = %s, want %s", inherited.Addr(), ln.Addr())
+	}
+
+	for _, spec := range []string{"fd:http", "fd:-1", "ftp:host"} {
+		if _, err := listenSpec(spec); err == nil {
+			t.Errorf("listenSpec(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestSystemdListener(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		name    string
+		listen  string
+		pid     string
+		fds     string
+		names   string
+		wantErr string
+	}{
+		{"other process", "systemd", "1", "1", "", "no sockets passed"},
+		{"no sockets", "systemd", pid, "0", "", "no sockets passed"},
+		{"invalid count", "systemd", pid, "many", "", "no sockets passed"},
+		{"unknown name", "systemd:admin", pid, "2", "http:metrics", `no socket named "admin"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LISTEN_PID", tt.pid)
+			t.Setenv("LISTEN_FDS", tt.fds)
+			t.Setenv("LISTEN_FDNAMES", tt.names)
+			_, err := listenSpec(tt.listen)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+			// The variables are not passed on to child processes
+			for _, name := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+				if _, ok := os.LookupEnv(name); ok {
+					t.Errorf("%s is still set", name)
+				}
+			}
+		})
+	}
+
+	// Sockets are numbered from 3 in the order of LISTEN_FDNAMES, so name the
+	// duplicated descriptor after as many other sockets as it is above 3
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	fd := listenerFD(t, ln)
+	names := strings.Split(strings.Repeat("other:", fd-systemdListenFDsStart)+"http", ":")
+	t.Setenv("LISTEN_PID", pid)
+	t.Setenv("LISTEN_FDS", strconv.Itoa(len(names)))
+	t.Setenv("LISTEN_FDNAMES", strings.Join(names, ":"))
+
+	inherited, err := listenSpec("systemd:http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inherited.Close()
+	if inherited.Addr().String() != ln.Addr().String() {
+		t.Errorf("Addr = %s, want %s", inherited.Addr(), ln.Addr())
+	}
+}
@@ -0,0 +1,225 @@
+package feature
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+var (
+	CodeBadGateway = bizerr.Register(bizerr.Definition{
+		Code: "bad_gateway", HTTPCode: http.StatusBadGateway,
+		MessageID: "error.bad_gateway", Message: "bad gateway",
+		Description: "The upstream of a proxy route failed",
+	})
+	CodeGatewayTimeout = bizerr.Register(bizerr.Definition{
+		Code: "gateway_timeout", HTTPCode: http.StatusGatewayTimeout,
+		MessageID: "error.gateway_timeout", Message: "gateway timeout",
+		Description: "The upstream of a proxy route did not answer in time",
+	})
+)
+
+// proxyHandler forwards the requests of r to its upstream. Transport errors
+// are rendered as 502, or 504 when the upstream did not answer in time.
+func (f *serverFeature) proxyHandler(r contracts.Route) (gin.HandlerFunc, error) {
+	p := r.Proxy
+	upstream, err := url.Parse(p.Upstream)
+	if err != nil || upstream.Scheme == "" || upstream.Host == "" {
+		return nil, fmt.Errorf("invalid proxy upstream %q of %s %s", p.Upstream, r.Method, r.Path)
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	backoff := p.RetryBackoff
+	if backoff == 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxRetryBody := p.RetryMaxBodySize
+	if maxRetryBody <= 0 {
+		maxRetryBody = 1 << 20
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.ResponseHeaderTimeout = timeout
+	transport := &retryTransport{base: base, retries: p.Retries, backoff: backoff}
+
+	return func(c *gin.Context) {
+		if p.Retries > 0 && idempotentMethod(c.Request.Method) && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			// Keep the body to replay it on retries, unless it is too large
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRetryBody+1))
+			if err != nil {
+				f.handleError(c, contracts.BodyError(err))
+				return
+			}
+			if int64(len(body)) > maxRetryBody {
+				// Without GetBody the request is not retried
+				c.Request.Body = streamedBody{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+			} else {
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
+				c.Request.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(body)), nil
+				}
+			}
+		}
+
+		proxy := &httputil.ReverseProxy{
+			Transport: transport,
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				path := strings.TrimPrefix(pr.In.URL.Path, p.StripPrefix)
+				if p.Rewrite != nil {
+					path = p.Rewrite(path)
+				}
+				pr.SetURL(upstream)
+				pr.Out.URL.Path = singleJoiningSlash(upstream.Path, path)
+				pr.Out.URL.RawPath = ""
+				if p.PreserveHost {
+					pr.Out.Host = pr.In.Host
+				}
+
+				pr.SetXForwarded()
+				// Use the client IP resolved through the trusted proxies
+				pr.Out.Header.Set("X-Forwarded-For", c.ClientIP())
+				if requestID := logger.RequestID(pr.In.Context()); requestID != "" {
+					pr.Out.Header.Set(contracts.RequestIDHeader, requestID)
+				}
+				for _, name := range p.RemoveHeaders {
+					pr.Out.Header.Del(name)
+				}
+				for name, value := range p.SetHeaders {
+					pr.Out.Header.Set(name, value)
+				}
+			},
+			ModifyResponse: func(resp *http.Response) error {
+				// Headers set by Aurora middlewares (request ID, CORS, security) win over the upstream ones
+				for name := range c.Writer.Header() {
+					if name != "Vary" {
+						resp.Header.Del(name)
+					}
+				}
+				for _, name := range p.RemoveResponseHeaders {
+					resp.Header.Del(name)
+				}
+				for name, value := range p.SetResponseHeaders {
+					resp.Header.Set(name, value)
+				}
+				return nil
+			},
+			ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+				f.handleError(c, proxyError(err))
+			},
+		}
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}, nil
+}
+
+// proxyError maps a transport error to 504 for timeouts and 502 otherwise
+func proxyError(err error) bizerr.BizError {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return bizerr.Wrap(err, CodeGatewayTimeout.Code, "")
+	}
+	return bizerr.Wrap(err, CodeBadGateway.Code, "")
+}
+
+// streamedBody is a request body whose start was already read
+type streamedBody struct {
+	io.Reader
+	io.Closer
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func singleJoiningSlash(a, b string) string {
+	switch {
+	case b == "":
+		if a == "" {
+			return "/"
+		}
+		return a
+	case strings.HasSuffix(a, "/") && strings.HasPrefix(b, "/"):
+		return a + b[1:]
+	case !strings.HasSuffix(a, "/") && !strings.HasPrefix(b, "/"):
+		return a + "/" + b
+	}
+	return a + b
+}
+
+// retryTransport retries idempotent requests after connection errors and 502, 503 and 504 responses
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !t.retryable(req, resp, err) {
+			return resp, err
+		}
+
+		var body io.ReadCloser
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			if body, err = req.GetBody(); err != nil {
+				if resp != nil {
+					resp.Body.Close()
+				}
+				return nil, err
+			}
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff * time.Duration(attempt+1)):
+		}
+		logger.InfoContext(req.Context(), "retrying proxy request %s %s (attempt %d)", req.Method, req.URL.Redacted(), attempt+2)
+
+		req = req.Clone(req.Context())
+		if body != nil {
+			req.Body = body
+		}
+	}
+}
+
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if !idempotentMethod(req.Method) || req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
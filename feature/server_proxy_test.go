@@ -0,0 +1,158 @@
+package feature
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func TestProxyRoutes(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Upstream-Path", r.URL.Path)
+		w.Header().Set("X-Internal", "secret")
+		w.Header().Set("X-Tenant", r.Header.Get("X-Tenant"))
+		w.Header().Set("X-Request-Id-Seen", r.Header.Get(contracts.RequestIDHeader))
+		_, _ = io.WriteString(w, "upstream "+r.Method)
+	}))
+	defer upstream.Close()
+
+	requireAuth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			contracts.AbortWithError(c, bizerr.ErrUnauthorized())
+			return
+		}
+		c.Next()
+	}
+	proxy := &contracts.Proxy{
+		Upstream:              upstream.URL + "/internal",
+		StripPrefix:           "/legacy",
+		SetHeaders:            map[string]string{"X-Tenant": "acme"},
+		RemoveResponseHeaders: []string{"X-Internal"},
+	}
+	billing := &contracts.Proxy{Upstream: upstream.URL}
+
+	routes := contracts.ProxyRoutes("/legacy/*path", proxy, requireAuth)
+	routes = append(routes, contracts.Versions("/api",
+		contracts.APIVersion{Name: "v1", Routes: contracts.ProxyRoutes("/billing/*path", billing)},
+		contracts.APIVersion{Name: "v2", Routes: []contracts.Route{{Method: "GET", Path: "/users", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"users": []string{}}, nil
+		}}}},
+	)...)
+	f := newTestServer(t, newTestApp(t), routes)
+	server := httptest.NewServer(f.Engine)
+	defer server.Close()
+
+	do := func(method, path string, header ...string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := do("POST", "/legacy/orders/1", "Authorization", "Bearer token")
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "upstream POST" {
+		t.Fatalf("status = %d, body = %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("X-Upstream-Path"); got != "/internal/orders/1" {
+		t.Errorf("upstream path = %q, want /internal/orders/1", got)
+	}
+	if resp.Header.Get("X-Tenant") != "acme" || resp.Header.Get("X-Internal") != "" {
+		t.Errorf("headers = %v", resp.Header)
+	}
+	if resp.Header.Get("X-Request-Id-Seen") == "" || resp.Header.Get("X-Request-Id-Seen") != resp.Header.Get(contracts.RequestIDHeader) {
+		t.Errorf("request ID was not forwarded: %v", resp.Header)
+	}
+
+	if resp := do("GET", "/legacy/orders/1"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", resp.StatusCode)
+	}
+
+	// Proxy routes are inherited by later API versions
+	for _, path := range []string{"/api/v1/billing/invoices", "/api/v2/billing/invoices"} {
+		resp := do("GET", path)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Upstream-Path") != path {
+			t.Errorf("GET %s: status = %d, upstream path = %q", path, resp.StatusCode, resp.Header.Get("X-Upstream-Path"))
+		}
+	}
+}
+
+func TestProxyUnreachableUpstream(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	f := newTestServer(t, newTestApp(t), contracts.ProxyRoutes("/down/*path", &contracts.Proxy{Upstream: upstream.URL}))
+	server := httptest.NewServer(f.Engine)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/down/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("status = %d, body = %q", resp.StatusCode, body)
+	}
+}
+
+func TestProxyRetries(t *testing.T) {
+	var bodies []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "stored")
+	}))
+	defer upstream.Close()
+
+	f := newTestServer(t, newTestApp(t), contracts.ProxyRoutes("/files/*path", &contracts.Proxy{
+		Upstream:         upstream.URL,
+		Retries:          1,
+		RetryBackoff:     time.Millisecond,
+		RetryMaxBodySize: 16,
+	}))
+	server := httptest.NewServer(f.Engine)
+	defer server.Close()
+
+	put := func(body string) int {
+		t.Helper()
+		req, _ := http.NewRequest("PUT", server.URL+"/files/a.txt", strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Small bodies are buffered and replayed
+	if status := put("small"); status != http.StatusOK || len(bodies) != 2 || bodies[1] != "small" {
+		t.Fatalf("small body: status = %d, upstream bodies = %q", status, bodies)
+	}
+
+	// Larger bodies are streamed whole and not retried
+	bodies = nil
+	large := strings.Repeat("x", 64)
+	if status := put(large); status != http.StatusServiceUnavailable || len(bodies) != 1 || bodies[0] != large {
+		t.Fatalf("large body: status = %d, upstream bodies = %q", status, bodies)
+	}
+}
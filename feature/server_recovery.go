@@ -0,0 +1,105 @@
+package feature
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// PanicReport describes a panic recovered while handling a request
+type PanicReport struct {
+	Request   *http.Request
+	RequestID string
+	Value     interface{}
+	Stack     []byte
+}
+
+// PanicReporter receives recovered panics, e.g. to send them to an error tracker.
+// It runs synchronously before the error response is written.
+type PanicReporter func(report *PanicReport)
+
+// WithPanicReporter sets a hook called for every panic recovered in a handler or middleware.
+func WithPanicReporter(reporter PanicReporter) ServerOption {
+	return func(f *serverFeature) {
+		f.panicReporter = reporter
+	}
+}
+
+// recoveryMiddleware turns panics into an internal server error rendered by
+// the error handler. The panic value and stack are logged with the request ID
+// but never sent to the client.
+func (f *serverFeature) recoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			// http.ErrAbortHandler aborts the response on purpose; let net/http handle it
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			ctx := c.Request.Context()
+			if isBrokenPipe(value) {
+				logger.ErrorContext(ctx, "%s %s: client connection lost: %v", c.Request.Method, c.Request.URL.Path, value)
+				c.Abort()
+				return
+			}
+
+			stack := debug.Stack()
+			logger.ErrorContext(ctx, "panic recovered in %s %s: %v\n%s", c.Request.Method, c.Request.URL.Path, value, stack)
+			f.reportPanic(&PanicReport{
+				Request:   c.Request,
+				RequestID: logger.RequestID(ctx),
+				Value:     value,
+				Stack:     stack,
+			})
+
+			c.Abort()
+			if !c.Writer.Written() {
+				f.handleError(c, bizerr.CodeInternalServerError.New())
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+func (f *serverFeature) reportPanic(report *PanicReport) {
+	if f.panicReporter == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("panic reporter failed: %v", r)
+		}
+	}()
+	f.panicReporter(report)
+}
+
+// isBrokenPipe reports whether a panic was caused by the client closing the connection
+func isBrokenPipe(value interface{}) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if errors.As(opErr, &syscallErr) {
+		msg := strings.ToLower(syscallErr.Error())
+		return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+	}
+	return false
+}
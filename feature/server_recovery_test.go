@@ -0,0 +1,110 @@
+package feature
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// panicRoutes serves GET and POST on each path, panicking with its value
+func panicRoutes(values map[string]interface{}) []contracts.Route {
+	routes := make([]contracts.Route, 0, 2*len(values))
+	for path, value := range values {
+		value := value
+		handler := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			if c.Query("partial") != "" {
+				c.String(http.StatusOK, "partial")
+			}
+			panic(value)
+		}
+		routes = append(routes,
+			contracts.Route{Method: "GET", Path: path, Handler: handler},
+			contracts.Route{Method: "POST", Path: path, Handler: handler},
+		)
+	}
+	return routes
+}
+
+func TestRecovery(t *testing.T) {
+	var reports []*PanicReport
+	f := newTestServer(t, newTestApp(t), panicRoutes(map[string]interface{}{
+		"/panic":         "database password is hunter2",
+		"/abort":         http.ErrAbortHandler,
+		"/broken-pipe":   &net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.EPIPE)},
+		"/reset-by-peer": &net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.ECONNRESET)},
+	}), WithPanicReporter(func(report *PanicReport) {
+		reports = append(reports, report)
+	}))
+
+	w := serve(f.Engine, httptest.NewRequest("GET", "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	body := decodeBody(t, w)
+	if body["code"] != bizerr.CodeInternalServerError.Code || body["request_id"] == "" || body["request_id"] != w.Header().Get(contracts.RequestIDHeader) {
+		t.Errorf("body = %v, request ID header = %q", body, w.Header().Get(contracts.RequestIDHeader))
+	}
+	if strings.Contains(w.Body.String(), "hunter2") {
+		t.Errorf("panic value sent to the client: %s", w.Body.String())
+	}
+	if len(reports) != 1 {
+		t.Fatalf("%d reports, want 1", len(reports))
+	}
+	report := reports[0]
+	if report.Value != "database password is hunter2" || report.RequestID != body["request_id"] ||
+		report.Request.URL.Path != "/panic" || !strings.Contains(string(report.Stack), "TestRecovery") {
+		t.Errorf("report = %+v", report)
+	}
+
+	// A buffered GET response is replaced by the error
+	w = serve(f.Engine, httptest.NewRequest("GET", "/panic?partial=1", nil))
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "partial") {
+		t.Errorf("buffered partial response: %d %q", w.Code, w.Body.String())
+	}
+	// A response already sent is not followed by the error
+	w = serve(f.Engine, httptest.NewRequest("POST", "/panic?partial=1", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "partial" || len(reports) != 3 {
+		t.Errorf("partial response: %d %q, %d reports", w.Code, w.Body.String(), len(reports))
+	}
+
+	// Broken connections are logged but neither reported nor answered
+	for _, path := range []string{"/broken-pipe", "/reset-by-peer"} {
+		w := serve(f.Engine, httptest.NewRequest("POST", path, nil))
+		if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+			t.Errorf("%s: response %d %q", path, w.Code, w.Body.String())
+		}
+	}
+	if len(reports) != 3 {
+		t.Errorf("%d reports after broken connections, want 3", len(reports))
+	}
+
+	func() {
+		defer func() {
+			if value := recover(); value != http.ErrAbortHandler {
+				t.Errorf("recovered %v, want http.ErrAbortHandler", value)
+			}
+		}()
+		serve(f.Engine, httptest.NewRequest("GET", "/abort", nil))
+	}()
+	if len(reports) != 3 {
+		t.Errorf("%d reports after an aborted handler, want 3", len(reports))
+	}
+}
+
+func TestRecoveryReporterPanic(t *testing.T) {
+	f := newTestServer(t, newTestApp(t), panicRoutes(map[string]interface{}{"/panic": "boom"}), WithPanicReporter(func(*PanicReport) {
+		panic("error tracker is down")
+	}))
+
+	w := serve(f.Engine, httptest.NewRequest("GET", "/panic", nil))
+	if w.Code != http.StatusInternalServerError || decodeBody(t, w)["code"] != bizerr.CodeInternalServerError.Code {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
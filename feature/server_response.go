@@ -0,0 +1,271 @@
+package feature
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// responseMediaTypes maps each response format to the media types that select it.
+// The first media type is the canonical one.
+var responseMediaTypes = map[string][]string{
+	config.ResponseFormatJSON:     {"application/json", "text/json"},
+	config.ResponseFormatXML:      {"application/xml", "text/xml"},
+	config.ResponseFormatYAML:     {"application/yaml", "application/x-yaml", "text/yaml"},
+	config.ResponseFormatMsgPack:  {"application/msgpack", "application/x-msgpack"},
+	config.ResponseFormatProtobuf: {"application/x-protobuf", "application/protobuf"},
+}
+
+// WithResponseEnvelope wraps every successful response body with fn.
+// RESPONSE_ENVELOPE=true enables the default {"code", "data", "message"} envelope.
+func WithResponseEnvelope(fn contracts.EnvelopeFunc) ServerOption {
+	return func(f *serverFeature) {
+		f.envelope = fn
+	}
+}
+
+// DefaultEnvelope wraps data into {"code": 0, "data": data, "message": "success"}.
+func DefaultEnvelope(c *contracts.RequestContext, status int, data interface{}) interface{} {
+	return gin.H{
+		"code":    0,
+		"data":    data,
+		"message": "success",
+	}
+}
+
+func (f *serverFeature) loadResponseConfig() error {
+	cfg := &config.ResponseConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load response config: %w", err)
+	}
+
+	if len(cfg.Formats) == 0 {
+		cfg.Formats = []string{config.ResponseFormatJSON}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("response config validation failed: %w", err)
+	}
+
+	if cfg.Envelope && f.envelope == nil {
+		f.envelope = DefaultEnvelope
+	}
+
+	f.responseConfig = cfg
+	return nil
+}
+
+// writeResult renders a handler result. Streams and files are written by
+// writeStream, a *contracts.Response controls status, headers and cookies,
+// and any other value is rendered with status 200.
+func (f *serverFeature) writeResult(reqCtx *contracts.RequestContext, data interface{}) {
+	if f.writeStream(reqCtx, data) {
+		return
+	}
+
+	c := reqCtx.Context
+	status := http.StatusOK
+
+	var resp *contracts.Response
+	switch v := data.(type) {
+	case *contracts.Response:
+		resp = v
+	case contracts.Response:
+		resp = &v
+	}
+
+	if resp != nil {
+		for key, values := range resp.Headers {
+			for _, value := range values {
+				c.Writer.Header().Add(key, value)
+			}
+		}
+		for _, cookie := range resp.Cookies {
+			http.SetCookie(c.Writer, cookie)
+		}
+		if resp.Status != 0 {
+			status = resp.Status
+		}
+		data = resp.Body
+
+		if view, ok := data.(*contracts.View); ok {
+			f.writeView(reqCtx, status, view)
+			return
+		}
+		if data == nil {
+			c.Status(status)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
+
+	if view, ok := data.(*contracts.View); ok {
+		f.writeView(reqCtx, status, view)
+		return
+	}
+	if f.envelope != nil {
+		data = f.envelope(reqCtx, status, data)
+	}
+
+	f.render(c, status, data)
+}
+
+// writeView renders a view as HTML with the contracts.ViewRenderer of the
+// template feature. The view is rendered in full before anything is written,
+// so that template errors are rendered through the error handler.
+func (f *serverFeature) writeView(reqCtx *contracts.RequestContext, status int, view *contracts.View) {
+	c := reqCtx.Context
+	var renderer contracts.ViewRenderer
+	if err := f.App.Find(&renderer); err != nil {
+		f.handleError(c, bizerr.ErrInternalServerError(errors.New("views require the template feature")))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, reqCtx, view); err != nil {
+		logger.ErrorContext(c.Request.Context(), "failed to render view %s: %v", view.Name, err)
+		f.handleError(c, bizerr.ErrInternalServerError(errors.New("failed to render view")))
+		return
+	}
+
+	if view.Status != 0 {
+		status = view.Status
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// render writes obj in the format negotiated from the Accept header.
+func (f *serverFeature) render(c *gin.Context, status int, obj interface{}) {
+	switch f.negotiateFormat(c, obj) {
+	case config.ResponseFormatXML:
+		c.XML(status, obj)
+	case config.ResponseFormatYAML:
+		c.YAML(status, obj)
+	case config.ResponseFormatMsgPack:
+		c.Render(status, render.MsgPack{Data: obj})
+	case config.ResponseFormatProtobuf:
+		c.ProtoBuf(status, obj)
+	default:
+		c.JSON(status, obj)
+	}
+}
+
+// negotiateFormat picks the configured format with the highest Accept quality.
+// Protobuf is only offered when obj is a proto.Message. JSON is the fallback.
+func (f *serverFeature) negotiateFormat(c *gin.Context, obj interface{}) string {
+	formats := []string{config.ResponseFormatJSON}
+	if f.responseConfig != nil {
+		formats = f.responseConfig.Formats
+	}
+	if len(formats) == 1 && formats[0] == config.ResponseFormatJSON {
+		return config.ResponseFormatJSON
+	}
+	// The body depends on Accept whenever there is a choice of format
+	addVary(c.Writer.Header(), "Accept")
+
+	accept := c.GetHeader("Accept")
+	if accept == "" {
+		for _, format := range formats {
+			if formatOffered(format, obj) {
+				return format
+			}
+		}
+		return config.ResponseFormatJSON
+	}
+
+	best, bestQ := "", 0.0
+	for _, spec := range parseAccept(accept) {
+		for _, format := range formats {
+			if formatOffered(format, obj) && spec.q > bestQ && mediaTypeMatches(spec.mediaType, format) {
+				best, bestQ = format, spec.q
+			}
+		}
+	}
+
+	if best == "" {
+		return config.ResponseFormatJSON
+	}
+	return best
+}
+
+// addVary adds the names missing from the Vary header
+func addVary(header http.Header, names ...string) {
+	for _, name := range names {
+		if !containsString(header.Values("Vary"), name) {
+			header.Add("Vary", name)
+		}
+	}
+}
+
+// formatOffered reports whether obj can be rendered in format
+func formatOffered(format string, obj interface{}) bool {
+	if format == config.ResponseFormatProtobuf {
+		_, ok := obj.(proto.Message)
+		return ok
+	}
+	return true
+}
+
+type acceptSpec struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses an Accept header, ordered by descending quality.
+func parseAccept(header string) []acceptSpec {
+	var specs []acceptSpec
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			specs = append(specs, acceptSpec{mediaType: mediaType, q: q})
+		}
+	}
+
+	sort.SliceStable(specs, func(i, j int) bool {
+		return specs[i].q > specs[j].q
+	})
+	return specs
+}
+
+func mediaTypeMatches(mediaType, format string) bool {
+	candidates := responseMediaTypes[format]
+	if mediaType == "*/*" {
+		return true
+	}
+	for _, candidate := range candidates {
+		if mediaType == candidate {
+			return true
+		}
+		if strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(candidate, strings.TrimSuffix(mediaType, "*")) {
+			return true
+		}
+	}
+	return false
+}
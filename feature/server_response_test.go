@@ -0,0 +1,97 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+type testItem struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+func newResponseServer(t *testing.T) *serverFeature {
+	t.Helper()
+
+	return newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/item", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return testItem{ID: 1, Name: "book"}, nil
+		}},
+		{Method: "GET", Path: "/missing", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return nil, bizerr.ErrNotFound()
+		}},
+	})
+}
+
+func TestContentNegotiation(t *testing.T) {
+	t.Setenv("RESPONSE_FORMATS", "json,xml")
+	f := newResponseServer(t)
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/xml", "application/xml"},
+		{"text/html, application/xml;q=0.9", "application/xml"},
+		{"application/json;q=0.5, application/xml;q=0.8", "application/xml"},
+		{"text/html", "application/json"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/item", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := serve(f.Engine, req)
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), tt.contentType) {
+			t.Errorf("Accept %q: status = %d, Content-Type = %q, want %s", tt.accept, w.Code, w.Header().Get("Content-Type"), tt.contentType)
+		}
+		if vary := w.Header().Values("Vary"); len(vary) != 1 || vary[0] != "Accept" {
+			t.Errorf("Accept %q: Vary = %v, want [Accept]", tt.accept, vary)
+		}
+	}
+
+	// Errors are negotiated too
+	if w := serve(f.Engine, httptest.NewRequest("GET", "/missing", nil)); w.Header().Get("Vary") != "Accept" {
+		t.Errorf("error response: Vary = %v", w.Header().Values("Vary"))
+	}
+}
+
+func TestJSONOnlyResponsesDoNotVary(t *testing.T) {
+	f := newResponseServer(t)
+
+	req := httptest.NewRequest("GET", "/item", nil)
+	req.Header.Set("Accept", "application/xml")
+	if w := serve(f.Engine, req); w.Header().Get("Vary") != "" {
+		t.Errorf("Vary = %v, want none", w.Header().Values("Vary"))
+	}
+}
+
+func TestProtobufFormatOnlyRendersProtoMessages(t *testing.T) {
+	t.Setenv("RESPONSE_FORMATS", "protobuf,json")
+	f := newResponseServer(t)
+
+	for _, accept := range []string{"", "*/*", "application/x-protobuf"} {
+		for _, path := range []string{"/item", "/missing"} {
+			req := httptest.NewRequest("GET", path, nil)
+			if accept != "" {
+				req.Header.Set("Accept", accept)
+			}
+			w := serve(f.Engine, req)
+			if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+				t.Errorf("Accept %q %s: Content-Type = %q, want JSON", accept, path, w.Header().Get("Content-Type"))
+			}
+		}
+	}
+
+	w := serve(f.Engine, httptest.NewRequest("GET", "/missing", nil))
+	if body := decodeBody(t, w); w.Code != http.StatusNotFound || body["code"] != bizerr.CodeNotFound.Code {
+		t.Fatalf("status = %d, body = %v", w.Code, body)
+	}
+}
@@ -0,0 +1,144 @@
+//go:build unix
+
+package feature
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// restartReadyFDEnv names the file descriptor the child writes to once it serves
+	restartReadyFDEnv = "AURORA_RESTART_READY_FD"
+	// defaultRestartTimeout bounds how long the parent waits for the child to be ready
+	defaultRestartTimeout = 30 * time.Second
+)
+
+// restartSignals returns the channel receiving restart requests (SIGUSR2)
+func (f *serverFeature) restartSignals() <-chan os.Signal {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR2)
+	return ch
+}
+
+// restart starts the binary again with the listening socket as file
+// descriptor 3 (LISTEN=fd:3), and returns once the child serves on it. The
+// caller then drains this process with shutdownServer. On error the child is
+// killed and this process keeps serving.
+func (f *serverFeature) restart() error {
+	f.mu.Lock()
+	ln := f.listener
+	f.mu.Unlock()
+
+	lnConn, err := listenerConn(ln)
+	if err != nil {
+		return err
+	}
+
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+
+	path, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		readyW.Close()
+		return fmt.Errorf("failed to find executable: %w", err)
+	}
+
+	// The socket is passed as is: an *os.File of it, as used by exec.Cmd,
+	// switches the shared socket to blocking mode, and this process would
+	// then block in accept and never finish draining
+	var pid int
+	controlErr := lnConn.Control(func(fd uintptr) {
+		pid, err = syscall.ForkExec(path, append([]string{path}, os.Args[1:]...), &syscall.ProcAttr{
+			Env:   append(os.Environ(), "LISTEN=fd:3", restartReadyFDEnv+"=4"),
+			Files: []uintptr{os.Stdin.Fd(), os.Stdout.Fd(), os.Stderr.Fd(), fd, readyW.Fd()},
+		})
+	})
+	readyW.Close()
+	if controlErr != nil {
+		err = controlErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start new process: %w", err)
+	}
+	// FindProcess always succeeds on Unix
+	process, _ := os.FindProcess(pid)
+
+	// Read returns once the child writes, or EOF when it exits without doing so
+	readyCh := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		readyCh <- err
+	}()
+
+	timeout := f.Config.RestartTimeout
+	if timeout == 0 {
+		timeout = defaultRestartTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-readyCh:
+		if err == io.EOF {
+			err = fmt.Errorf("new process exited before it was ready")
+		}
+	case <-timer.C:
+		err = fmt.Errorf("new process not ready after %v", timeout)
+	}
+	if err != nil {
+		_ = process.Kill()
+		_, _ = process.Wait()
+		return err
+	}
+
+	// Child processes are reaped by the init process once this one exits
+	go process.Wait()
+
+	// The socket file now belongs to the new process
+	if ul, ok := ln.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+	log.Printf("New process %d is ready, draining connections", pid)
+	return nil
+}
+
+// listenerConn returns the socket of ln, to pass it to a new process
+func listenerConn(ln net.Listener) (syscall.RawConn, error) {
+	conn, ok := ln.(syscall.Conn)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass a %T to a new process", ln)
+	}
+	return conn.SyscallConn()
+}
+
+// notifyRestartReady tells the parent process of a restart that this one serves
+func notifyRestartReady() {
+	value := os.Getenv(restartReadyFDEnv)
+	if value == "" {
+		return
+	}
+	os.Unsetenv(restartReadyFDEnv)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+	file := os.NewFile(uintptr(fd), "restart-ready")
+	if file == nil {
+		return
+	}
+	defer file.Close()
+	_, _ = file.Write([]byte{1})
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package feature
+
+import (
+	"errors"
+	"os"
+)
+
+// restartSignals returns nil: graceful restart relies on SIGUSR2 and file
+// descriptor inheritance, which are only available on Unix
+func (f *serverFeature) restartSignals() <-chan os.Signal {
+	return nil
+}
+
+func (f *serverFeature) restart() error {
+	return errors.New("graceful restart is not supported on this platform")
+}
+
+func notifyRestartReady() {}
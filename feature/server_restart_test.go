@@ -0,0 +1,186 @@
+//go:build unix
+
+package feature
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// restartChildEnv makes TestRestartHelperProcess act as the new process of a restart
+const restartChildEnv = "AURORA_TEST_RESTART_CHILD"
+
+// restartRoutes answers with the PID of the serving process; /stop closes stop
+func restartRoutes(stop chan struct{}) []contracts.Route {
+	var once sync.Once
+	return []contracts.Route{
+		{Method: "GET", Path: "/pid", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"pid": os.Getpid()}, nil
+		}},
+		{Method: "POST", Path: "/stop", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			once.Do(func() { close(stop) })
+			return gin.H{}, nil
+		}},
+	}
+}
+
+// servingPID returns the PID of the process answering on addr, over a new connection
+func servingPID(t *testing.T, addr string) int {
+	t.Helper()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + addr + "/pid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct{ PID int }
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body.PID
+}
+
+// startRestartServer starts a server on PORT=0 serving restartRoutes
+func startRestartServer(t *testing.T, stop chan struct{}) *serverFeature {
+	t.Helper()
+
+	a := newTestApp(t)
+	f := NewServerFeature().(*serverFeature)
+	a.AddFeature(f)
+	f.RegisterRoutes(restartRoutes(stop))
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// withArgs runs fn with os.Args replaced, as they are used to start the new process
+func withArgs(args []string, fn func()) {
+	saved := os.Args
+	os.Args = args
+	defer func() { os.Args = saved }()
+	fn()
+}
+
+// TestRestartHelperProcess is the new process started by TestRestart. It
+// serves on the inherited socket until it is asked to stop.
+func TestRestartHelperProcess(t *testing.T) {
+	if os.Getenv(restartChildEnv) == "" {
+		t.Skip("started by TestRestart")
+	}
+
+	stop := make(chan struct{})
+	f := startRestartServer(t, stop)
+	select {
+	case <-stop:
+	case <-time.After(10 * time.Second):
+	}
+	f.Close()
+}
+
+func TestRestart(t *testing.T) {
+	t.Setenv("RESTART_TIMEOUT", "10s")
+	f := startRestartServer(t, make(chan struct{}))
+	addr := f.Addr().String()
+
+	// A new process that exits without serving leaves this one serving; the
+	// helper process skips without restartChildEnv
+	var err error
+	args := []string{os.Args[0], "-test.run=^TestRestartHelperProcess$"}
+	withArgs(args, func() { err = f.restart() })
+	if err == nil {
+		t.Fatal("restart succeeded without a ready process")
+	}
+	if pid := servingPID(t, addr); pid != os.Getpid() {
+		t.Fatalf("served by %d after a failed restart, want %d", pid, os.Getpid())
+	}
+
+	t.Setenv(restartChildEnv, "1")
+	withArgs(args, func() { err = f.restart() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.shutdownServer(); err != nil {
+		t.Fatal(err)
+	}
+	// Let signalListener return
+	f.stopChan <- syscall.SIGTERM
+	f.Wait()
+
+	// The socket stays open in the new process, so no connection is refused
+	pid := servingPID(t, addr)
+	if pid == os.Getpid() {
+		t.Fatal("still served by the old process")
+	}
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	if resp, err := client.Post("http://"+addr+"/stop", "application/json", nil); err == nil {
+		resp.Body.Close()
+	}
+	if process, err := os.FindProcess(pid); err == nil {
+		_, _ = process.Wait()
+	}
+}
+
+func TestListenerConn(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+	unix, err := net.Listen("unix", t.TempDir()+"/app.sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close()
+
+	for _, ln := range []net.Listener{tcp, unix} {
+		if _, err := listenerConn(ln); err != nil {
+			t.Errorf("%s: %v", ln.Addr().Network(), err)
+		}
+	}
+	// Listeners without a socket cannot be passed on
+	if _, err := listenerConn(struct{ net.Listener }{tcp}); err == nil {
+		t.Error("wrapped listener accepted")
+	}
+}
+
+func TestNotifyRestartReady(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	fd, err := syscall.Dup(int(w.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(restartReadyFDEnv, strconv.Itoa(fd))
+	notifyRestartReady()
+	if _, ok := os.LookupEnv(restartReadyFDEnv); ok {
+		t.Errorf("%s is still set", restartReadyFDEnv)
+	}
+	if err := r.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 1 || err != nil {
+		t.Errorf("read %d bytes: %v", n, err)
+	}
+
+	// Without the variable, e.g. on a first start, nothing is written
+	notifyRestartReady()
+}
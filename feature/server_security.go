@@ -0,0 +1,124 @@
+package feature
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+var CodeIPNotAllowed = bizerr.Register(bizerr.Definition{
+	Code: "ip_not_allowed", HTTPCode: http.StatusForbidden,
+	MessageID: "error.ip_not_allowed", Message: "client IP not allowed",
+	Description: "The client IP is denied by IP_ALLOW_LIST or IP_DENY_LIST",
+})
+
+func (f *serverFeature) loadSecurityConfig() error {
+	cfg := &config.SecurityConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load security config: %w", err)
+	}
+
+	cfg.ApplyProfile(f.Config.RunLevel)
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("security config validation failed: %w", err)
+	}
+
+	f.securityConfig = cfg
+	return nil
+}
+
+// setupTrustedProxies makes c.ClientIP() use forwarding headers only from TRUSTED_PROXIES,
+// instead of gin's default of trusting every proxy
+func (f *serverFeature) setupTrustedProxies(engine *gin.Engine) error {
+	var proxies []string
+	if len(f.securityConfig.TrustedProxies) > 0 {
+		proxies = f.securityConfig.TrustedProxies
+	}
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	engine.TrustedPlatform = f.securityConfig.TrustedPlatform
+	return nil
+}
+
+// securityHeadersMiddleware sets the configured security headers on every response
+func (f *serverFeature) securityHeadersMiddleware() gin.HandlerFunc {
+	cfg := f.securityConfig
+	headers := make(map[string]string)
+
+	if cfg.HSTSMaxAge > 0 {
+		hsts := "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+		headers["Strict-Transport-Security"] = hsts
+	}
+	for name, value := range map[string]string{
+		"Content-Security-Policy": cfg.ContentSecurityPolicy,
+		"X-Frame-Options":         cfg.FrameOptions,
+		"X-Content-Type-Options":  cfg.ContentTypeOptions,
+		"Referrer-Policy":         cfg.ReferrerPolicy,
+	} {
+		if value != "" && value != config.SecurityHeaderOff {
+			headers[name] = value
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		for name, value := range headers {
+			header.Set(name, value)
+		}
+		c.Next()
+	}
+}
+
+// ipFilterMiddleware rejects clients whose IP, as resolved through the trusted
+// proxies, is in IP_DENY_LIST or, when IP_ALLOW_LIST is set, not in it.
+// Clients without an IP, such as those of a Unix socket, can only be on the
+// allow list, so they are rejected only when it is set.
+func (f *serverFeature) ipFilterMiddleware() (gin.HandlerFunc, error) {
+	allow, err := config.ParseIPNets(f.securityConfig.IPAllowList)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := config.ParseIPNets(f.securityConfig.IPDenyList)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		if !ipAllowed(net.ParseIP(c.ClientIP()), allow, deny) {
+			contracts.AbortWithError(c, CodeIPNotAllowed.New())
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+func ipAllowed(ip net.IP, allow, deny []*net.IPNet) bool {
+	if ip == nil {
+		return len(allow) == 0
+	}
+	return !containsIP(deny, ip) && (len(allow) == 0 || containsIP(allow, ip))
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
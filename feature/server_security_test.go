@@ -0,0 +1,166 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// clientIPRoutes answers with the client IP resolved by gin
+func clientIPRoutes() []contracts.Route {
+	return []contracts.Route{
+		{Method: "GET", Path: "/ip", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"ip": c.ClientIP()}, nil
+		}},
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		header map[string]string
+	}{
+		{
+			name: "production profile",
+			env:  map[string]string{"RUN_LEVEL": "production"},
+			header: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000",
+				"X-Frame-Options":           "DENY",
+				"X-Content-Type-Options":    "nosniff",
+				"Referrer-Policy":           "strict-origin-when-cross-origin",
+				"Content-Security-Policy":   "",
+			},
+		},
+		{
+			name:   "local profile",
+			env:    map[string]string{"RUN_LEVEL": "local"},
+			header: map[string]string{"Strict-Transport-Security": "", "X-Frame-Options": "DENY"},
+		},
+		{
+			name: "configured",
+			env: map[string]string{
+				"RUN_LEVEL":                        "production",
+				"SECURITY_HSTS_MAX_AGE":            "63072000",
+				"SECURITY_HSTS_INCLUDE_SUBDOMAINS": "true",
+				"SECURITY_HSTS_PRELOAD":            "true",
+				"SECURITY_CSP":                     "default-src 'self'",
+				"SECURITY_FRAME_OPTIONS":           "off",
+				"SECURITY_REFERRER_POLICY":         "no-referrer",
+			},
+			header: map[string]string{
+				"Strict-Transport-Security": "max-age=63072000; includeSubDomains; preload",
+				"Content-Security-Policy":   "default-src 'self'",
+				"X-Frame-Options":           "",
+				"Referrer-Policy":           "no-referrer",
+			},
+		},
+		{
+			name:   "disabled",
+			env:    map[string]string{"RUN_LEVEL": "production", "SECURITY_HEADERS_DISABLED": "true"},
+			header: map[string]string{"Strict-Transport-Security": "", "X-Frame-Options": "", "X-Content-Type-Options": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			f := newTestServer(t, newTestApp(t), clientIPRoutes())
+
+			// Error responses carry the headers too
+			for _, path := range []string{"/ip", "/missing"} {
+				w := serve(f.Engine, httptest.NewRequest("GET", path, nil))
+				for name, value := range tt.header {
+					if got := w.Header().Get(name); got != value {
+						t.Errorf("%s: %s = %q, want %q", path, name, got, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name           string
+		runLevel       string
+		trustedProxies string
+		remoteAddr     string
+		forwardedFor   string
+		ip             string
+	}{
+		{"production trusts no proxy", "production", "", "192.0.2.1:4711", "203.0.113.7", "192.0.2.1"},
+		{"local trusts loopback", "local", "", "127.0.0.1:4711", "203.0.113.7", "203.0.113.7"},
+		{"local ignores other proxies", "local", "", "192.0.2.1:4711", "203.0.113.7", "192.0.2.1"},
+		{"trusted proxy", "production", "192.0.2.0/24", "192.0.2.1:4711", "203.0.113.7", "203.0.113.7"},
+		// The client prepends a spoofed IP; only the entry added by the trusted proxy counts
+		{"spoofed chain", "production", "192.0.2.0/24", "192.0.2.1:4711", "10.0.0.1, 203.0.113.7", "203.0.113.7"},
+		{"untrusted proxy", "production", "192.0.2.0/24", "198.51.100.9:4711", "10.0.0.1", "198.51.100.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RUN_LEVEL", tt.runLevel)
+			if tt.trustedProxies != "" {
+				t.Setenv("TRUSTED_PROXIES", tt.trustedProxies)
+			}
+			f := newTestServer(t, newTestApp(t), clientIPRoutes())
+
+			req := httptest.NewRequest("GET", "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			if body := decodeBody(t, serve(f.Engine, req)); body["ip"] != tt.ip {
+				t.Errorf("client IP = %v, want %s", body["ip"], tt.ip)
+			}
+		})
+	}
+}
+
+func TestIPFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowList  string
+		denyList   string
+		remoteAddr string
+		status     int
+	}{
+		{"allowed", "192.0.2.0/24", "", "192.0.2.1:4711", http.StatusOK},
+		{"not allowed", "192.0.2.0/24", "", "198.51.100.1:4711", http.StatusForbidden},
+		{"denied", "", "198.51.100.0/24", "198.51.100.1:4711", http.StatusForbidden},
+		{"not denied", "", "198.51.100.0/24", "192.0.2.1:4711", http.StatusOK},
+		{"deny list wins", "192.0.2.0/24", "192.0.2.66", "192.0.2.66:4711", http.StatusForbidden},
+		{"allowed next to a denied IP", "192.0.2.0/24", "192.0.2.66", "192.0.2.67:4711", http.StatusOK},
+		{"IPv6", "2001:db8::/32", "", "[2001:db8::1]:4711", http.StatusOK},
+		// Unix socket clients have no IP
+		{"no IP with a deny list", "", "198.51.100.0/24", "@", http.StatusOK},
+		{"no IP with an allow list", "192.0.2.0/24", "", "@", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.allowList != "" {
+				t.Setenv("IP_ALLOW_LIST", tt.allowList)
+			}
+			if tt.denyList != "" {
+				t.Setenv("IP_DENY_LIST", tt.denyList)
+			}
+			f := newTestServer(t, newTestApp(t), clientIPRoutes())
+
+			req := httptest.NewRequest("GET", "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := serve(f.Engine, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status == http.StatusForbidden {
+				if body := decodeBody(t, w); body["code"] != CodeIPNotAllowed.Code {
+					t.Errorf("body = %v", body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package feature
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// precompressedVariants are the encodings of the precompressed files looked up
+// next to static files, in order of preference
+var precompressedVariants = []struct{ encoding, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// staticSite is a registered StaticSite, with the ETags of its files without
+// a modification time (embed.FS) cached
+type staticSite struct {
+	contracts.StaticSite
+	etags sync.Map
+}
+
+func (f *serverFeature) RegisterStaticSites(sites []contracts.StaticSite) {
+	for _, site := range sites {
+		site.Prefix = "/" + strings.Trim(site.Prefix, "/")
+		if site.Index == "" {
+			site.Index = "index.html"
+		}
+		f.staticSites = append(f.staticSites, &staticSite{StaticSite: site})
+	}
+	// Longest prefixes first, so that nested sites win
+	sort.SliceStable(f.staticSites, func(i, j int) bool {
+		return len(f.staticSites[i].Prefix) > len(f.staticSites[j].Prefix)
+	})
+}
+
+// setupNoRoute serves the static sites on the paths without a route, and
+// renders the other unknown paths as 404 through the error handler
+func (f *serverFeature) setupNoRoute() error {
+	for _, site := range f.staticSites {
+		if site.FS == nil {
+			return fmt.Errorf("static site %s has no FS", site.Prefix)
+		}
+	}
+
+	f.Engine.NoRoute(func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			for _, site := range f.staticSites {
+				if name, ok := site.match(c.Request.URL.Path); ok {
+					served, err := site.serve(c, name)
+					if err != nil {
+						f.handleError(c, bizerr.ErrInternalServerError(err))
+						return
+					}
+					if served {
+						return
+					}
+					break
+				}
+			}
+		}
+		f.handleError(c, bizerr.ErrNotFound())
+	})
+	return nil
+}
+
+// match returns the name in the FS of the site of a request path under its prefix
+func (s *staticSite) match(urlPath string) (string, bool) {
+	if s.Prefix != "/" && urlPath != s.Prefix && !strings.HasPrefix(urlPath, s.Prefix+"/") {
+		return "", false
+	}
+	name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(urlPath, s.Prefix)), "/")
+	if name == "" {
+		name = "."
+	}
+	return name, true
+}
+
+// serve writes the file name, the index of the directory name or the SPA
+// fallback, and reports false when there is none of them
+func (s *staticSite) serve(c *gin.Context, name string) (bool, error) {
+	info, err := fs.Stat(s.FS, name)
+	if err == nil && info.IsDir() {
+		name = path.Join(name, s.Index)
+		info, err = fs.Stat(s.FS, name)
+	}
+	if err != nil || info.IsDir() {
+		if !s.fallback(c, name) {
+			return false, nil
+		}
+		name = s.Index
+	}
+
+	if err := s.serveFile(c, name); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// fallback reports whether a missing file is a client-side route of a single-page app
+func (s *staticSite) fallback(c *gin.Context, name string) bool {
+	if !s.SPA || path.Ext(name) != "" || !strings.Contains(c.GetHeader("Accept"), "text/html") {
+		return false
+	}
+	for _, prefix := range s.Exclude {
+		if strings.HasPrefix(c.Request.URL.Path, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *staticSite) serveFile(c *gin.Context, name string) error {
+	header := c.Writer.Header()
+	served, encoding := name, ""
+	// Ranges apply to the identity encoding
+	if c.GetHeader("Range") == "" {
+		for _, variant := range precompressedVariants {
+			if negotiateEncoding(c.GetHeader("Accept-Encoding"), []string{variant.encoding}) == "" {
+				continue
+			}
+			if info, err := fs.Stat(s.FS, name+variant.ext); err == nil && !info.IsDir() {
+				served, encoding = name+variant.ext, variant.encoding
+				break
+			}
+		}
+	}
+
+	file, err := s.FS.Open(served)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+
+	etag, err := s.etag(served, info, content)
+	if err != nil {
+		return err
+	}
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", s.cacheControl(name))
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+	}
+	if encoding != "" && !containsString(header.Values("Vary"), "Accept-Encoding") {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	http.ServeContent(c.Writer, c.Request, name, info.ModTime(), content)
+	return nil
+}
+
+// etag identifies a file by its modification time and size, or by a hash of
+// its content when it has no modification time
+func (s *staticSite) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`W/"%x-%x"`, info.ModTime().UnixNano(), info.Size()), nil
+	}
+	if etag, ok := s.etags.Load(name); ok {
+		return etag.(string), nil
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+	s.etags.Store(name, etag)
+	return etag, nil
+}
+
+// cacheControl lets browsers revalidate index files, and cache fingerprinted
+// files for a year and the others for MaxAge
+func (s *staticSite) cacheControl(name string) string {
+	if path.Base(name) == path.Base(s.Index) {
+		return "no-cache"
+	}
+	for _, pattern := range s.Immutable {
+		if ok, _ := path.Match(pattern, name); ok {
+			return "public, max-age=31536000, immutable"
+		}
+	}
+	if s.MaxAge > 0 {
+		return fmt.Sprintf("public, max-age=%d", int(s.MaxAge.Seconds()))
+	}
+	return "no-cache"
+}
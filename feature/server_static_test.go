@@ -0,0 +1,107 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func TestStaticSites(t *testing.T) {
+	a := newTestApp(t)
+	f := NewServerFeature().(*serverFeature)
+	a.AddFeature(f)
+	f.RegisterRoutes([]contracts.Route{
+		{Method: "GET", Path: "/admin/api/ping", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return gin.H{"pong": true}, nil
+		}},
+	})
+	f.RegisterStaticSites([]contracts.StaticSite{
+		{
+			Prefix: "/admin/",
+			FS: fstest.MapFS{
+				"index.html":            {Data: []byte("<html>admin</html>")},
+				"assets/app.1234.js":    {Data: []byte("console.log('admin')")},
+				"assets/app.1234.js.br": {Data: []byte("brotli")},
+				"robots.txt":            {Data: []byte("User-agent: *")},
+			},
+			SPA:       true,
+			Exclude:   []string{"/admin/api"},
+			MaxAge:    time.Hour,
+			Immutable: []string{"assets/*"},
+		},
+		{
+			Prefix: "/",
+			FS:     fstest.MapFS{"index.html": {Data: []byte("<html>home</html>")}},
+		},
+	})
+	if err := f.setupRoutes(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path         string
+		header       map[string]string
+		status       int
+		body         string
+		cacheControl string
+	}{
+		{"/admin", nil, http.StatusOK, "<html>admin</html>", "no-cache"},
+		{"/admin/", nil, http.StatusOK, "<html>admin</html>", "no-cache"},
+		{"/admin/robots.txt", nil, http.StatusOK, "User-agent: *", "public, max-age=3600"},
+		{"/admin/assets/app.1234.js", nil, http.StatusOK, "console.log('admin')", "public, max-age=31536000, immutable"},
+		{"/admin/assets/app.1234.js", map[string]string{"Accept-Encoding": "gzip, br"}, http.StatusOK, "brotli", "public, max-age=31536000, immutable"},
+		{"/admin/users/42", map[string]string{"Accept": "text/html"}, http.StatusOK, "<html>admin</html>", "no-cache"},
+		{"/admin/users/42", map[string]string{"Accept": "application/json"}, http.StatusNotFound, "", ""},
+		{"/admin/missing.js", map[string]string{"Accept": "text/html"}, http.StatusNotFound, "", ""},
+		{"/admin/api/missing", map[string]string{"Accept": "text/html"}, http.StatusNotFound, "", ""},
+		{"/admin/api/ping", nil, http.StatusOK, `{"pong":true}`, ""},
+		{"/", nil, http.StatusOK, "<html>home</html>", "no-cache"},
+		{"/missing", nil, http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		for name, value := range tt.header {
+			req.Header.Set(name, value)
+		}
+		w := serve(f.Engine, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %v: status = %d, want %d", tt.path, tt.header, w.Code, tt.status)
+			continue
+		}
+		if tt.status == http.StatusNotFound {
+			if body := decodeBody(t, w); body["code"] != bizerr.CodeNotFound.Code {
+				t.Errorf("%s %v: body = %v", tt.path, tt.header, body)
+			}
+			continue
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s %v: body = %q, want %q", tt.path, tt.header, w.Body.String(), tt.body)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tt.cacheControl {
+			t.Errorf("%s %v: Cache-Control = %q, want %q", tt.path, tt.header, got, tt.cacheControl)
+		}
+	}
+
+	w := serve(f.Engine, httptest.NewRequest("GET", "/admin/assets/app.1234.js", nil))
+	if w.Header().Get("Content-Type") != "text/javascript; charset=utf-8" || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("asset headers = %v", w.Header())
+	}
+	req := httptest.NewRequest("GET", "/admin/assets/app.1234.js", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	if w := serve(f.Engine, req); w.Code != http.StatusNotModified {
+		t.Errorf("conditional request: status = %d, want 304", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/admin/assets/app.1234.js", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	if w := serve(f.Engine, req); w.Header().Get("Content-Encoding") != "br" || w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("precompressed asset headers = %v", w.Header())
+	}
+}
@@ -0,0 +1,309 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// writeStream renders streaming handler results and reports whether data was one.
+func (f *serverFeature) writeStream(reqCtx *contracts.RequestContext, data interface{}) bool {
+	switch v := data.(type) {
+	case *contracts.EventStream:
+		f.writeEventStream(reqCtx.Context, v)
+	case *contracts.NDJSONStream:
+		f.writeNDJSONStream(reqCtx.Context, v)
+	case *contracts.FileResponse:
+		f.writeFile(reqCtx.Context, v)
+	default:
+		return false
+	}
+	return true
+}
+
+// streamContext returns a context that is cancelled when the client disconnects
+// or the server starts shutting down.
+func (f *serverFeature) streamContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	stop := context.AfterFunc(f.shutdownCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// disableWriteDeadline lifts WRITE_TIMEOUT for long-lived responses.
+func disableWriteDeadline(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+}
+
+// finishStream reports a handler error: through the error handler if nothing
+// has been sent yet, otherwise through fail, since the status is already written.
+func (f *serverFeature) finishStream(c *gin.Context, ctx context.Context, err error, fail func(message string)) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	if !c.Writer.Written() {
+		f.handleError(c, err)
+		return
+	}
+
+	message := "internal server error"
+	var bizErr bizerr.BizError
+	if errors.As(err, &bizErr) {
+		message = bizErr.Message()
+	}
+	logger.ErrorContext(c.Request.Context(), "stream %s %s aborted: %v", c.Request.Method, c.Request.URL.Path, err)
+	fail(message)
+}
+
+type sseWriter struct {
+	c           *gin.Context
+	ctx         context.Context
+	retry       time.Duration
+	lastEventID string
+	mu          sync.Mutex
+}
+
+func (w *sseWriter) start() {
+	if w.c.Writer.Written() {
+		return
+	}
+	header := w.c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.c.Writer.WriteHeader(http.StatusOK)
+	if w.retry > 0 {
+		_, _ = w.c.Writer.WriteString("retry:" + strconv.FormatInt(w.retry.Milliseconds(), 10) + "\n\n")
+	}
+}
+
+func (w *sseWriter) Send(event contracts.SSEEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	w.start()
+
+	if err := sse.Encode(w.c.Writer, sse.Event{
+		Id:    event.ID,
+		Event: event.Event,
+		Retry: uint(event.Retry / time.Millisecond),
+		Data:  event.Data,
+	}); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// heartbeat writes a comment line every interval until ctx is done. It shares
+// the writer lock with Send so heartbeats and events never interleave.
+func (w *sseWriter) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if ctx.Err() != nil {
+				w.mu.Unlock()
+				return
+			}
+			w.start()
+			_, err := w.c.Writer.WriteString(": ping\n\n")
+			w.c.Writer.Flush()
+			w.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (w *sseWriter) LastEventID() string {
+	return w.lastEventID
+}
+
+func (w *sseWriter) Context() context.Context {
+	return w.ctx
+}
+
+func (f *serverFeature) writeEventStream(c *gin.Context, stream *contracts.EventStream) {
+	ctx, cancel := f.streamContext(c)
+	defer cancel()
+	disableWriteDeadline(c)
+
+	w := &sseWriter{
+		c:           c,
+		ctx:         ctx,
+		retry:       stream.Retry,
+		lastEventID: c.GetHeader("Last-Event-ID"),
+	}
+
+	stopHeartbeat := func() {}
+	if stream.Heartbeat > 0 {
+		var heartbeatCtx context.Context
+		heartbeatCtx, stopHeartbeat = context.WithCancel(ctx)
+		go w.heartbeat(heartbeatCtx, stream.Heartbeat)
+	}
+
+	err := stream.Handler(w)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stopHeartbeat()
+	f.finishStream(c, ctx, err, func(message string) {
+		_ = sse.Encode(c.Writer, sse.Event{Event: "error", Data: gin.H{"message": message}})
+		c.Writer.Flush()
+	})
+}
+
+type ndjsonWriter struct {
+	c   *gin.Context
+	ctx context.Context
+}
+
+func (w *ndjsonWriter) Write(v interface{}) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	if !w.c.Writer.Written() {
+		w.c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+		w.c.Writer.Header().Set("X-Accel-Buffering", "no")
+		w.c.Writer.WriteHeader(http.StatusOK)
+	}
+	if err := json.NewEncoder(w.c.Writer).Encode(v); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+func (w *ndjsonWriter) Context() context.Context {
+	return w.ctx
+}
+
+func (f *serverFeature) writeNDJSONStream(c *gin.Context, stream *contracts.NDJSONStream) {
+	ctx, cancel := f.streamContext(c)
+	defer cancel()
+	disableWriteDeadline(c)
+
+	err := stream.Handler(&ndjsonWriter{c: c, ctx: ctx})
+	f.finishStream(c, ctx, err, func(message string) {
+		_ = json.NewEncoder(c.Writer).Encode(gin.H{"error": gin.H{"message": message}})
+		c.Writer.Flush()
+	})
+}
+
+// contextReader stops reading once ctx is done, so downloads end on shutdown.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+type contextReadSeeker struct {
+	contextReader
+	s io.Seeker
+}
+
+func (r *contextReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return r.s.Seek(offset, whence)
+}
+
+func (f *serverFeature) writeFile(c *gin.Context, file *contracts.FileResponse) {
+	ctx, cancel := f.streamContext(c)
+	defer cancel()
+	disableWriteDeadline(c)
+
+	reader, name, modTime := file.Reader, file.Name, file.ModTime
+	if file.Path != "" {
+		fh, err := os.Open(file.Path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				f.handleError(c, bizerr.ErrNotFound())
+			} else {
+				f.handleError(c, bizerr.ErrInternalServerError(err))
+			}
+			return
+		}
+		defer fh.Close()
+
+		info, err := fh.Stat()
+		if err != nil || info.IsDir() {
+			f.handleError(c, bizerr.ErrNotFound())
+			return
+		}
+		reader = fh
+		if name == "" {
+			name = filepath.Base(file.Path)
+		}
+		if modTime.IsZero() {
+			modTime = info.ModTime()
+		}
+	} else if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if reader == nil {
+		f.handleError(c, bizerr.ErrNotFound())
+		return
+	}
+
+	header := c.Writer.Header()
+	if file.ContentType != "" {
+		header.Set("Content-Type", file.ContentType)
+	}
+	disposition := "attachment"
+	if file.Inline {
+		disposition = "inline"
+	}
+	if name != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": name}))
+	}
+
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, name, modTime, &contextReadSeeker{
+			contextReader: contextReader{ctx: ctx, r: seeker},
+			s:             seeker,
+		})
+		return
+	}
+
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(c.Writer, &contextReader{ctx: ctx, r: reader}); err != nil && ctx.Err() == nil {
+		logger.ErrorContext(c.Request.Context(), "download %s %s aborted: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+}
@@ -0,0 +1,188 @@
+package feature
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func TestEventStream(t *testing.T) {
+	stream := func(s *contracts.EventStream) contracts.CustomizedHandlerFunc {
+		return func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return s, nil
+		}
+	}
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/events", Handler: stream(contracts.NewEventStream(func(w contracts.SSEWriter) error {
+			if err := w.Send(contracts.SSEEvent{ID: "1", Event: "resumed", Data: w.LastEventID()}); err != nil {
+				return err
+			}
+			return w.Send(contracts.SSEEvent{ID: "2", Data: gin.H{"total": 7}})
+		}).WithRetry(3 * time.Second))},
+		{Method: "GET", Path: "/heartbeat", Handler: stream(contracts.NewEventStream(func(w contracts.SSEWriter) error {
+			time.Sleep(50 * time.Millisecond)
+			return w.Send(contracts.SSEEvent{Data: "done"})
+		}).WithHeartbeat(5 * time.Millisecond))},
+		{Method: "GET", Path: "/fail-early", Handler: stream(contracts.NewEventStream(func(w contracts.SSEWriter) error {
+			return bizerr.ErrForbidden()
+		}))},
+		{Method: "GET", Path: "/fail-late", Handler: stream(contracts.NewEventStream(func(w contracts.SSEWriter) error {
+			if err := w.Send(contracts.SSEEvent{Data: "first"}); err != nil {
+				return err
+			}
+			return errors.New("database is gone")
+		}))},
+	})
+
+	req := httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Last-Event-ID", "41")
+	w := serve(f.Engine, req)
+	want := "retry:3000\n\nid:1\nevent:resumed\ndata:41\n\nid:2\ndata:{\"total\":7}\n\n"
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("events: %d %q, want %q", w.Code, w.Body.String(), want)
+	}
+	if w.Header().Get("Content-Type") != "text/event-stream" || w.Header().Get("Cache-Control") != "no-cache" || w.Header().Get("X-Accel-Buffering") != "no" {
+		t.Errorf("events headers = %v", w.Header())
+	}
+
+	w = serve(f.Engine, httptest.NewRequest("GET", "/heartbeat", nil))
+	if body := w.Body.String(); !strings.HasPrefix(body, ": ping\n\n") || !strings.HasSuffix(body, "data:done\n\n") {
+		t.Errorf("heartbeat body = %q", body)
+	}
+
+	// Errors before the first event are rendered by the error handler
+	w = serve(f.Engine, httptest.NewRequest("GET", "/fail-early", nil))
+	if w.Code != http.StatusForbidden || decodeBody(t, w)["code"] != bizerr.CodeForbidden.Code {
+		t.Errorf("fail-early: %d %s", w.Code, w.Body.String())
+	}
+
+	// Later errors end the stream with an error event, without the cause
+	w = serve(f.Engine, httptest.NewRequest("GET", "/fail-late", nil))
+	want = "data:first\n\nevent:error\ndata:{\"message\":\"internal server error\"}\n\n"
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("fail-late: %d %q, want %q", w.Code, w.Body.String(), want)
+	}
+}
+
+func TestNDJSONStream(t *testing.T) {
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/export", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.NewNDJSONStream(func(w contracts.StreamWriter) error {
+				for i := 1; i <= 3; i++ {
+					if err := w.Write(gin.H{"id": i}); err != nil {
+						return err
+					}
+				}
+				if c.Query("fail") != "" {
+					return bizerr.ErrBadRequest(errors.New("cursor expired"))
+				}
+				return nil
+			}), nil
+		}},
+	})
+
+	w := serve(f.Engine, httptest.NewRequest("GET", "/export", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n" || w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("export: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	w = serve(f.Engine, httptest.NewRequest("GET", "/export?fail=1", nil))
+	if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 4 || lines[3] != `{"error":{"message":"cursor expired"}}` {
+		t.Errorf("failed export = %q", w.Body.String())
+	}
+}
+
+func TestStreamShutdown(t *testing.T) {
+	var sendErr, writeErr error
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/events", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.NewEventStream(func(w contracts.SSEWriter) error {
+				<-w.Context().Done()
+				sendErr = w.Send(contracts.SSEEvent{Data: "late"})
+				return sendErr
+			}), nil
+		}},
+		{Method: "GET", Path: "/export", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.NewNDJSONStream(func(w contracts.StreamWriter) error {
+				<-w.Context().Done()
+				writeErr = w.Write(gin.H{"late": true})
+				return writeErr
+			}), nil
+		}},
+	})
+	f.cancelStreams()
+
+	for _, path := range []string{"/events", "/export"} {
+		if w := serve(f.Engine, httptest.NewRequest("GET", path, nil)); w.Body.Len() != 0 {
+			t.Errorf("%s: body after shutdown = %q", path, w.Body.String())
+		}
+	}
+	if sendErr == nil || writeErr == nil {
+		t.Errorf("writes after shutdown: %v, %v", sendErr, writeErr)
+	}
+}
+
+func TestFileResponse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.csv")
+	if err := os.WriteFile(path, []byte("id,total\n1,7\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/report", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.FileAttachment(path, "report 2024.csv"), nil
+		}},
+		{Method: "GET", Path: "/inline", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return &contracts.FileResponse{Path: path, Inline: true}, nil
+		}},
+		{Method: "GET", Path: "/generated", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			// A reader that is not an io.ReadSeeker is copied as is
+			return contracts.FileFromReader(io.MultiReader(strings.NewReader("generated")), "out.txt", ""), nil
+		}},
+		{Method: "GET", Path: "/missing", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.FileAttachment(filepath.Join(dir, "missing.csv"), ""), nil
+		}},
+		{Method: "GET", Path: "/directory", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.FileAttachment(dir, ""), nil
+		}},
+	})
+
+	w := serve(f.Engine, httptest.NewRequest("GET", "/report", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "id,total\n1,7\n" ||
+		w.Header().Get("Content-Disposition") != `attachment; filename="report 2024.csv"` || w.Header().Get("Last-Modified") == "" {
+		t.Errorf("report: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/report", nil)
+	req.Header.Set("Range", "bytes=3-7")
+	if w := serve(f.Engine, req); w.Code != http.StatusPartialContent || w.Body.String() != "total" {
+		t.Errorf("range: %d %q", w.Code, w.Body.String())
+	}
+
+	if w := serve(f.Engine, httptest.NewRequest("GET", "/inline", nil)); w.Header().Get("Content-Disposition") != `inline; filename=report.csv` {
+		t.Errorf("inline Content-Disposition = %q", w.Header().Get("Content-Disposition"))
+	}
+
+	w = serve(f.Engine, httptest.NewRequest("GET", "/generated", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "generated" || w.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Errorf("generated: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	for _, path := range []string{"/missing", "/directory"} {
+		w := serve(f.Engine, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusNotFound || decodeBody(t, w)["code"] != bizerr.CodeNotFound.Code {
+			t.Errorf("%s: %d %s", path, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package feature
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/shyandsy/aurora/app"
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// testEnv is the configuration of the test servers; tests set their own
+// variables with t.Setenv before creating the app
+var testEnv = map[string]string{
+	"HOST":                "127.0.0.1",
+	"PORT":                "0",
+	"READ_TIMEOUT":        "5s",
+	"WRITE_TIMEOUT":       "5s",
+	"IDLE_TIMEOUT":        "5s",
+	"SHUTDOWN_TIMEOUT":    "1s",
+	"SERVICE_NAME":        "test",
+	"SERVICE_VERSION":     "1.0.0",
+	"RUN_LEVEL":           "production",
+	"ACCESS_LOG_DISABLED": "true",
+}
+
+func newTestApp(t *testing.T) contracts.App {
+	t.Helper()
+
+	for key, value := range testEnv {
+		if _, ok := os.LookupEnv(key); !ok {
+			t.Setenv(key, value)
+		}
+	}
+	return app.NewApp()
+}
+
+// newTestServer adds the server feature to a and sets up routes
+func newTestServer(t *testing.T, a contracts.App, routes []contracts.Route, opts ...ServerOption) *serverFeature {
+	t.Helper()
+
+	f := NewServerFeature(opts...).(*serverFeature)
+	a.AddFeature(f)
+	f.RegisterRoutes(routes)
+	if err := f.setupRoutes(); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+func TestErrorMessages(t *testing.T) {
+	a := newTestApp(t)
+	translator := &mapTranslator{messages: map[string]map[string]string{
+		"en": {"error.not_found": "Nothing here"},
+		"fr": {"error.not_found": "Introuvable"},
+	}}
+	if err := a.ProvideAs(translator, (*contracts.Translator)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	f := newTestServer(t, a, []contracts.Route{
+		{Method: "GET", Path: "/missing", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return nil, bizerr.ErrNotFound()
+		}},
+		{Method: "GET", Path: "/forbidden", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return nil, bizerr.ErrForbidden()
+		}},
+		{Method: "GET", Path: "/invalid", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return nil, bizerr.ErrBadRequest(errors.New("name is required"))
+		}},
+	})
+
+	tests := []struct {
+		path    string
+		status  int
+		code    string
+		message string
+	}{
+		{"/missing", http.StatusNotFound, bizerr.CodeNotFound.Code, "Nothing here"},
+		{"/missing?lang=fr", http.StatusNotFound, bizerr.CodeNotFound.Code, "Introuvable"},
+		// Messages without a translation fall back to the definition
+		{"/forbidden", http.StatusForbidden, bizerr.CodeForbidden.Code, bizerr.CodeForbidden.Message},
+		// Client errors keep the message of their cause
+		{"/invalid", http.StatusBadRequest, bizerr.CodeBadRequest.Code, "name is required"},
+	}
+	for _, tt := range tests {
+		w := serve(f.Engine, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, w.Code, tt.status)
+			continue
+		}
+		if body := decodeBody(t, w); body["code"] != tt.code || body["message"] != tt.message || body["request_id"] == "" {
+			t.Errorf("%s: body = %v, want code %q and message %q", tt.path, body, tt.code, tt.message)
+		}
+	}
+}
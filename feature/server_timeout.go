@@ -0,0 +1,58 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// CodeRequestTimeout is rendered when a route runs past its timeout
+var CodeRequestTimeout = bizerr.Register(bizerr.Definition{
+	Code: "request_timeout", HTTPCode: http.StatusServiceUnavailable,
+	MessageID: "error.request_timeout", Message: "request timed out",
+	Description: "The route ran past its timeout",
+})
+
+// routeTimeout returns the timeout of r, falling back to HANDLER_TIMEOUT
+func (f *serverFeature) routeTimeout(r contracts.Route) time.Duration {
+	if r.Timeout != 0 {
+		return r.Timeout
+	}
+	return f.Config.HandlerTimeout
+}
+
+// timeoutMiddleware sets a deadline on the request context. The handler runs
+// on the request goroutine and is expected to honour the context; once it
+// returns past the deadline, CodeRequestTimeout is rendered instead of its
+// result, unless a response was already written.
+func (f *serverFeature) timeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if timedOut(c) && !c.Writer.Written() {
+			c.Abort()
+			f.writeTimeout(c)
+		}
+	}
+}
+
+// timedOut reports whether the deadline of the request context has passed
+func timedOut(c *gin.Context) bool {
+	return errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+func (f *serverFeature) writeTimeout(c *gin.Context) {
+	logger.ErrorContext(c.Request.Context(), "%s %s timed out", c.Request.Method, c.Request.URL.Path)
+	f.handleError(c, CodeRequestTimeout.New())
+}
@@ -0,0 +1,108 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Setenv("HANDLER_TIMEOUT", "20ms")
+
+	// wait blocks until the request context is done, as database calls do
+	wait := func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		<-c.Request.Context().Done()
+		return gin.H{"late": true}, nil
+	}
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/fast", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			_, hasDeadline := c.Request.Context().Deadline()
+			return gin.H{"deadline": hasDeadline}, nil
+		}},
+		{Method: "GET", Path: "/slow", Handler: wait},
+		{Method: "GET", Path: "/route-timeout", Timeout: time.Millisecond, Handler: wait},
+		{Method: "GET", Path: "/disabled", Timeout: -1, Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			time.Sleep(40 * time.Millisecond)
+			_, hasDeadline := c.Request.Context().Deadline()
+			return gin.H{"deadline": hasDeadline}, nil
+		}},
+		{Method: "GET", Path: "/written", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			c.String(http.StatusOK, "partial")
+			<-c.Request.Context().Done()
+			return nil, nil
+		}},
+	})
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/fast", http.StatusOK},
+		{"/slow", http.StatusServiceUnavailable},
+		{"/route-timeout", http.StatusServiceUnavailable},
+		{"/disabled", http.StatusOK},
+		{"/written", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := serve(f.Engine, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.path, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		switch tt.path {
+		case "/fast":
+			if body := decodeBody(t, w); body["deadline"] != true {
+				t.Errorf("%s: the request context has no deadline", tt.path)
+			}
+		case "/disabled":
+			if body := decodeBody(t, w); body["deadline"] != false {
+				t.Errorf("%s: the request context has a deadline", tt.path)
+			}
+		case "/written":
+			if w.Body.String() != "partial" {
+				t.Errorf("%s: body = %q", tt.path, w.Body.String())
+			}
+		default:
+			if body := decodeBody(t, w); body["code"] != CodeRequestTimeout.Code || body["late"] != nil {
+				t.Errorf("%s: body = %v", tt.path, body)
+			}
+		}
+	}
+}
+
+// stackRecorder records the stack of each error it handles
+type stackRecorder struct {
+	stacks []string
+}
+
+func (r *stackRecorder) HandleError(c *gin.Context, err error) {
+	r.stacks = append(r.stacks, bizerr.Stack(err))
+	c.AbortWithStatus(http.StatusServiceUnavailable)
+}
+
+func TestTimeoutErrorStack(t *testing.T) {
+	t.Setenv("HANDLER_TIMEOUT", "1ms")
+
+	recorder := &stackRecorder{}
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "GET", Path: "/slow", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			<-c.Request.Context().Done()
+			return nil, nil
+		}},
+	}, WithErrorHandler(recorder))
+
+	for i := 0; i < 2; i++ {
+		serve(f.Engine, httptest.NewRequest("GET", "/slow", nil))
+	}
+	// Each timeout is a new error whose stack starts where it was rendered
+	if len(recorder.stacks) != 2 || !strings.Contains(recorder.stacks[0], "writeTimeout") {
+		t.Fatalf("stacks = %q", recorder.stacks)
+	}
+}
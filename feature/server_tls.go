@@ -0,0 +1,149 @@
+package feature
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// defaultTLSReloadInterval bounds how often the certificate files are checked for changes
+const defaultTLSReloadInterval = 10 * time.Second
+
+// intermediateCipherSuites are the TLS 1.2 suites with forward secrecy and
+// AEAD ciphers. TLS 1.3 suites are not configurable.
+var intermediateCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// createTLSConfig builds the server TLS configuration, or returns nil when TLS is disabled
+func (f *serverFeature) createTLSConfig() (*tls.Config, error) {
+	cfg := f.Config
+	if !cfg.TLSEnabled() {
+		return nil, nil
+	}
+
+	interval := cfg.TLSReloadInterval
+	if interval == 0 {
+		interval = defaultTLSReloadInterval
+	}
+	reloader, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if cfg.TLSMinVersion == config.TLSVersion13 {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+	switch cfg.TLSCipherPolicy {
+	case config.TLSCipherPolicyIntermediate:
+		tlsConfig.CipherSuites = intermediateCipherSuites
+	case config.TLSCipherPolicyModern:
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+
+	if cfg.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS_CLIENT_CA_FILE: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("TLS_CLIENT_CA_FILE contains no PEM certificates")
+		}
+		tlsConfig.ClientCAs = pool
+	}
+	switch cfg.TLSClientAuth {
+	case config.TLSClientAuthOptional:
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case config.TLSClientAuthRequire:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// certReloader serves a certificate and key pair, reloading it when either
+// file changes. Files are checked on handshakes, at most once per interval,
+// so no goroutine is needed. A pair that fails to load keeps the previous one.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func newCertReloader(certFile, keyFile string, interval time.Duration) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, interval: interval}
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.checkedAt) >= r.interval {
+		r.checkedAt = now
+		modTime, err := r.filesModTime()
+		if err != nil {
+			logger.Error("TLS certificate check failed, keeping the current certificate: %v", err)
+		} else if !modTime.Equal(r.modTime) {
+			if err := r.load(modTime); err != nil {
+				logger.Error("TLS certificate reload failed, keeping the current certificate: %v", err)
+			} else {
+				logger.Info("TLS certificate reloaded from %s", r.certFile)
+			}
+		}
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// filesModTime returns the latest modification time of the certificate and key files
+func (r *certReloader) filesModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
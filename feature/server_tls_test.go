@@ -0,0 +1,204 @@
+package feature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert is a certificate and key issued for the TLS tests
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// issueTestCert issues a certificate for commonName, signed by parent or
+// self-signed when parent is nil. Certificates without a parent are CAs.
+func issueTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// write stores the certificate and key as PEM files, returning their paths
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, c.pem, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// tlsKeyPair returns c as a certificate for a tls.Config
+func (c *testCert) tlsKeyPair() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key, Leaf: c.cert}
+}
+
+// touch moves the modification time of the files forward, so that a rewrite
+// within the resolution of the file system is detected
+func touch(t *testing.T, offset time.Duration, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		modTime := time.Now().Add(offset)
+		if err := os.Chtimes(name, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := issueTestCert(t, "first", nil).write(t, dir, "server")
+
+	r, err := newCertReloader(certFile, keyFile, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commonName := func(r *certReloader) string {
+		t.Helper()
+		cert, err := r.GetCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return leaf.Subject.CommonName
+	}
+	if cn := commonName(r); cn != "first" {
+		t.Fatalf("certificate = %s, want first", cn)
+	}
+
+	issueTestCert(t, "second", nil).write(t, dir, "server")
+	touch(t, time.Second, certFile, keyFile)
+	if cn := commonName(r); cn != "second" {
+		t.Errorf("certificate after a change = %s, want second", cn)
+	}
+
+	// A broken pair keeps the previous certificate
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, 2*time.Second, certFile)
+	if cn := commonName(r); cn != "second" {
+		t.Errorf("certificate after a broken change = %s, want second", cn)
+	}
+
+	// Files are checked at most once per interval
+	otherCert, otherKey := issueTestCert(t, "third", nil).write(t, dir, "other")
+	throttled, err := newCertReloader(otherCert, otherKey, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	throttled.checkedAt = time.Now()
+	issueTestCert(t, "fourth", nil).write(t, dir, "other")
+	touch(t, time.Second, otherCert, otherKey)
+	if cn := commonName(throttled); cn != "third" {
+		t.Errorf("certificate within the interval = %s, want third", cn)
+	}
+
+	if _, err := newCertReloader(filepath.Join(dir, "missing.crt"), keyFile, 0); err == nil {
+		t.Error("missing certificate file accepted")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueTestCert(t, "Test CA", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := issueTestCert(t, "server", ca).write(t, dir, "server")
+
+	t.Setenv("TLS_CERT_FILE", certFile)
+	t.Setenv("TLS_KEY_FILE", keyFile)
+	t.Setenv("TLS_CLIENT_CA_FILE", caFile)
+	t.Setenv("TLS_CLIENT_AUTH", "require")
+	a := newTestApp(t)
+	f := NewServerFeature().(*serverFeature)
+	a.AddFeature(f)
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(clientCerts ...tls.Certificate) (*http.Response, error) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: clientCerts,
+		}}}
+		defer client.CloseIdleConnections()
+		resp, err := client.Get("https://" + f.Addr().String() + "/health")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	if _, err := get(); err == nil {
+		t.Error("request without a client certificate succeeded")
+	}
+	if _, err := get(issueTestCert(t, "intruder", issueTestCert(t, "Other CA", nil)).tlsKeyPair()); err == nil {
+		t.Error("request with a certificate of another CA succeeded")
+	}
+	resp, err := get(issueTestCert(t, "client", ca).tlsKeyPair())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.TLS == nil {
+		t.Errorf("status = %d, TLS = %v", resp.StatusCode, resp.TLS != nil)
+	}
+}
@@ -0,0 +1,201 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// DeprecationHook is called on each request to a deprecated route, e.g. to count them in metrics
+type DeprecationHook func(c *gin.Context, route contracts.Route)
+
+// WithDeprecationHook sets a hook called on each request to a deprecated route.
+func WithDeprecationHook(hook DeprecationHook) ServerOption {
+	return func(f *serverFeature) {
+		f.deprecationHook = hook
+	}
+}
+
+var errUnsupportedAPIVersion = errors.New("unsupported API version")
+
+// unsupportedVersionKey marks requests naming an unknown version in their context
+type unsupportedVersionKey struct{}
+
+func (f *serverFeature) loadAPIVersionConfig() error {
+	cfg := &config.APIVersionConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load API version config: %w", err)
+	}
+
+	if cfg.Strategy == "" {
+		cfg.Strategy = config.APIVersionByPath
+	}
+	if cfg.Header == "" {
+		cfg.Header = "API-Version"
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("API version config validation failed: %w", err)
+	}
+
+	f.apiVersionConfig = cfg
+	return nil
+}
+
+// apiVersionHandler routes requests without a version in their path to the
+// version named by the header or media type, by inserting it after the
+// version base: GET /api/users with API-Version: v2 is served by /api/v2/users.
+// Paths that already contain a version are served as they are, and paths
+// outside the version bases are not touched. With the path strategy the
+// version is only taken from the path, so next is returned as is.
+func (f *serverFeature) apiVersionHandler(next http.Handler) http.Handler {
+	cfg := f.apiVersionConfig
+	if cfg.Strategy == config.APIVersionByPath {
+		return next
+	}
+
+	var versions []string
+	known := make(map[string]bool)
+	baseSet := make(map[string]bool)
+	for _, r := range f.routes {
+		if r.Version == "" {
+			continue
+		}
+		if !known[r.Version] {
+			known[r.Version] = true
+			versions = append(versions, r.Version)
+		}
+		baseSet[r.VersionBase] = true
+	}
+	if len(versions) == 0 {
+		return next
+	}
+
+	bases := make([]string, 0, len(baseSet))
+	for base := range baseSet {
+		bases = append(bases, base)
+	}
+	// Longest first, so that nested bases match before their parents
+	sort.Slice(bases, func(i, j int) bool { return len(bases[i]) > len(bases[j]) })
+
+	defaultVersion := cfg.Default
+	if defaultVersion == "" {
+		defaultVersion = versions[len(versions)-1]
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base, ok := versionBase(r.URL.Path, bases)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var requested string
+		if cfg.Strategy == config.APIVersionByMediaType {
+			addVary(w.Header(), "Accept")
+			requested = mediaTypeVersion(r.Header.Get("Accept"), cfg.MediaType)
+		} else {
+			addVary(w.Header(), cfg.Header)
+			requested = strings.TrimSpace(r.Header.Get(cfg.Header))
+		}
+
+		version := defaultVersion
+		if known[requested] {
+			version = requested
+		} else if requested != "" {
+			// Versioned routes reject it, unversioned routes under the base ignore it
+			r = r.WithContext(context.WithValue(r.Context(), unsupportedVersionKey{}, requested))
+		}
+
+		if path, ok := versionedPath(r.URL.Path, base, version, known); ok {
+			r.URL.Path = path
+			r.URL.RawPath = ""
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// versionBase returns the longest of bases that path is equal to or under
+func versionBase(path string, bases []string) (string, bool) {
+	for _, base := range bases {
+		if path == base || strings.HasPrefix(path, base+"/") {
+			return base, true
+		}
+	}
+	return "", false
+}
+
+// versionedPath inserts version after base, unless the path already has a version
+func versionedPath(path, base, version string, known map[string]bool) (string, bool) {
+	rest := strings.TrimPrefix(path, base)
+	segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+	if known[segment] {
+		return path, false
+	}
+	return base + "/" + version + rest, true
+}
+
+// mediaTypeVersion returns the version of the first Accept entry of the form
+// mediaType.VERSION, optionally followed by a +suffix
+func mediaTypeVersion(accept, mediaType string) string {
+	prefix := strings.ToLower(mediaType) + "."
+	for _, part := range strings.Split(accept, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		version, _, _ := strings.Cut(strings.TrimPrefix(name, prefix), "+")
+		return version
+	}
+	return ""
+}
+
+// routeVersionMiddleware exposes the version of r and announces its
+// deprecation. Versioned routes reject requests naming an unknown version.
+func (f *serverFeature) routeVersionMiddleware(r contracts.Route) gin.HandlerFunc {
+	header := make(map[string]string)
+	if d := r.Deprecation; d != nil {
+		header["Deprecation"] = "true"
+		if !d.Since.IsZero() {
+			header["Deprecation"] = "@" + strconv.FormatInt(d.Since.Unix(), 10)
+		}
+		if !d.Sunset.IsZero() {
+			header["Sunset"] = d.Sunset.UTC().Format(http.TimeFormat)
+		}
+		if d.Link != "" {
+			header["Link"] = "<" + d.Link + `>; rel="deprecation"`
+		}
+	}
+
+	return func(c *gin.Context) {
+		if r.Version != "" {
+			if version, ok := c.Request.Context().Value(unsupportedVersionKey{}).(string); ok {
+				contracts.AbortWithError(c, bizerr.ErrBadRequest(fmt.Errorf("%w: %s", errUnsupportedAPIVersion, version)))
+				return
+			}
+			c.Set(contracts.ContextKeyAPIVersion, r.Version)
+		}
+		if r.Deprecation != nil {
+			for name, value := range header {
+				c.Writer.Header().Set(name, value)
+			}
+			logger.InfoContext(c.Request.Context(), "deprecated route called: %s %s", r.Method, r.Path)
+			if f.deprecationHook != nil {
+				f.deprecationHook(c, r)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,144 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+func versionHandler(name string) contracts.CustomizedHandlerFunc {
+	return func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+		return gin.H{"handler": name, "version": c.GetString(contracts.ContextKeyAPIVersion)}, nil
+	}
+}
+
+// versionedRoutes registers GET /api/v1/users and GET /api/v2/users, and an unversioned GET /ping
+func versionedRoutes() []contracts.Route {
+	routes := contracts.Versions("/api",
+		contracts.APIVersion{
+			Name:        "v1",
+			Deprecation: &contracts.Deprecation{Sunset: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+			Routes: []contracts.Route{
+				{Method: "GET", Path: "/users", Handler: versionHandler("users v1")},
+			},
+		},
+		contracts.APIVersion{
+			Name: "v2",
+			Routes: []contracts.Route{
+				{Method: "GET", Path: "/users", Handler: versionHandler("users v2")},
+			},
+		},
+	)
+	return append(routes, contracts.Route{Method: "GET", Path: "/ping", Handler: versionHandler("ping")})
+}
+
+type versionTest struct {
+	path    string
+	header  string
+	status  int
+	handler string
+	vary    string
+}
+
+func runVersionTests(t *testing.T, handler http.Handler, header string, tests []versionTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(header, tt.header)
+		}
+		w := serve(handler, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %q: status = %d, want %d: %s", tt.path, tt.header, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		if got := strings.Join(w.Header().Values("Vary"), ","); got != tt.vary {
+			t.Errorf("%s %q: Vary = %q, want %q", tt.path, tt.header, got, tt.vary)
+		}
+		body := decodeBody(t, w)
+		if tt.handler != "" && body["handler"] != tt.handler {
+			t.Errorf("%s %q: body = %v", tt.path, tt.header, body)
+		}
+		if tt.status == http.StatusBadRequest && body["code"] != bizerr.CodeBadRequest.Code {
+			t.Errorf("%s %q: body = %v", tt.path, tt.header, body)
+		}
+	}
+}
+
+func TestPathVersioning(t *testing.T) {
+	f := newTestServer(t, newTestApp(t), versionedRoutes())
+	handler := f.apiVersionHandler(f.Engine)
+
+	// The version header is neither read nor varied on
+	runVersionTests(t, handler, "API-Version", []versionTest{
+		{"/api/v1/users", "", http.StatusOK, "users v1", ""},
+		{"/api/v2/users", "v1", http.StatusOK, "users v2", ""},
+		{"/api/v2/users", "v9", http.StatusOK, "users v2", ""},
+		{"/api/users", "", http.StatusNotFound, "", ""},
+		{"/api/users", "v1", http.StatusNotFound, "", ""},
+		{"/ping", "", http.StatusOK, "ping", ""},
+	})
+}
+
+func TestMediaTypeVersioning(t *testing.T) {
+	t.Setenv("API_VERSION_STRATEGY", "media_type")
+	t.Setenv("API_VERSION_MEDIA_TYPE", "application/vnd.test")
+	f := newTestServer(t, newTestApp(t), versionedRoutes())
+	handler := f.apiVersionHandler(f.Engine)
+
+	runVersionTests(t, handler, "Accept", []versionTest{
+		{"/api/users", "", http.StatusOK, "users v2", "Accept"},
+		{"/api/users", "application/vnd.test.v1+json", http.StatusOK, "users v1", "Accept"},
+		{"/api/users", "text/html, application/vnd.test.v1+json;q=0.9", http.StatusOK, "users v1", "Accept"},
+		{"/api/users", "application/json", http.StatusOK, "users v2", "Accept"},
+		{"/api/v2/users", "application/vnd.test.v1+json", http.StatusOK, "users v2", "Accept"},
+		{"/api/users", "application/vnd.test.v9+json", http.StatusBadRequest, "", "Accept"},
+		{"/ping", "application/vnd.test.v9+json", http.StatusOK, "ping", ""},
+	})
+}
+
+func TestDefaultVersion(t *testing.T) {
+	t.Setenv("API_VERSION_STRATEGY", "header")
+	t.Setenv("API_VERSION_HEADER", "X-Version")
+	t.Setenv("API_VERSION_DEFAULT", "v1")
+	f := newTestServer(t, newTestApp(t), versionedRoutes())
+	handler := f.apiVersionHandler(f.Engine)
+
+	runVersionTests(t, handler, "X-Version", []versionTest{
+		{"/api/users", "", http.StatusOK, "users v1", "X-Version"},
+		{"/api/users", "v2", http.StatusOK, "users v2", "X-Version"},
+	})
+}
+
+func TestHeaderVersioning(t *testing.T) {
+	t.Setenv("API_VERSION_STRATEGY", "header")
+	f := newTestServer(t, newTestApp(t), versionedRoutes())
+	handler := f.apiVersionHandler(f.Engine)
+
+	runVersionTests(t, handler, "API-Version", []versionTest{
+		{"/api/users", "", http.StatusOK, "users v2", "API-Version"},
+		{"/api/users", "v1", http.StatusOK, "users v1", "API-Version"},
+		{"/api/users", "v2", http.StatusOK, "users v2", "API-Version"},
+		{"/api/v1/users", "", http.StatusOK, "users v1", "API-Version"},
+		{"/api/users", "v9", http.StatusBadRequest, "", "API-Version"},
+		{"/api/v1/users", "v9", http.StatusBadRequest, "", "API-Version"},
+		// Paths outside the version base ignore the header
+		{"/ping", "v9", http.StatusOK, "ping", ""},
+		{"/health", "v9", http.StatusOK, "", ""},
+	})
+
+	req := httptest.NewRequest("GET", "/api/users", nil)
+	req.Header.Set("API-Version", "v1")
+	w := serve(handler, req)
+	if w.Header().Get("Deprecation") != "true" || w.Header().Get("Sunset") != "Tue, 01 Jan 2030 00:00:00 GMT" || w.Header().Get("Vary") != "API-Version" {
+		t.Errorf("v1 headers = %v", w.Header())
+	}
+}
@@ -0,0 +1,109 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/websocket"
+)
+
+// redisBroker fans WebSocket broadcasts out through a Redis channel.
+type redisBroker struct {
+	redis   RedisService
+	channel string
+}
+
+// NewRedisBroker returns a websocket.Broker that publishes and subscribes on channel.
+func NewRedisBroker(redis RedisService, channel string) websocket.Broker {
+	return &redisBroker{redis: redis, channel: channel}
+}
+
+func (b *redisBroker) Publish(ctx context.Context, payload []byte) error {
+	return b.redis.Publish(ctx, b.channel, payload)
+}
+
+func (b *redisBroker) Subscribe(ctx context.Context, fn func(payload []byte)) error {
+	return b.redis.Subscribe(ctx, b.channel, func(payload string) {
+		fn([]byte(payload))
+	})
+}
+
+func (f *serverFeature) RegisterWebSocketRoutes(routes []contracts.WebSocketRoute) {
+	f.wsRoutes = append(f.wsRoutes, routes...)
+}
+
+func (f *serverFeature) loadWebSocketConfig() error {
+	cfg := &config.WebSocketConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to load WebSocket config: %w", err)
+	}
+
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	if cfg.MaxMessageSize == 0 {
+		cfg.MaxMessageSize = 1 << 20
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("WebSocket config validation failed: %w", err)
+	}
+
+	f.wsConfig = cfg
+	f.hub = websocket.NewHub(websocket.HubOptions{PingInterval: cfg.PingInterval})
+	f.upgrader = &websocket.Upgrader{
+		ReadLimit:      cfg.MaxMessageSize,
+		WriteTimeout:   cfg.WriteTimeout,
+		AllowedOrigins: cfg.AllowedOrigins,
+	}
+	return nil
+}
+
+// setupWebSockets registers the WebSocket routes and, when WEBSOCKET_REDIS_CHANNEL
+// is set, connects the hub to Redis for cross-instance broadcasts.
+func (f *serverFeature) setupWebSockets() error {
+	if len(f.wsRoutes) == 0 {
+		return nil
+	}
+
+	var broker websocket.Broker
+	if f.wsConfig.RedisChannel != "" {
+		var redis RedisService
+		if err := f.App.Find(&redis); err != nil {
+			return fmt.Errorf("WEBSOCKET_REDIS_CHANNEL requires the redis feature: %w", err)
+		}
+		broker = NewRedisBroker(redis, f.wsConfig.RedisChannel)
+	}
+	f.hub.Run(broker)
+
+	for _, r := range f.wsRoutes {
+		handlers := append(r.Middlewares, f.createWebSocketHandler(r))
+		f.Engine.GET(r.Path, handlers...)
+	}
+	return nil
+}
+
+func (f *serverFeature) createWebSocketHandler(route contracts.WebSocketRoute) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var translator contracts.Translator
+		f.App.Find(&translator)
+
+		reqCtx := &contracts.RequestContext{
+			Context:    c,
+			App:        f.App,
+			Translator: translator,
+		}
+
+		if bizErr := f.hub.Serve(reqCtx, route, f.upgrader); bizErr != nil {
+			f.handleError(c, bizErr)
+		}
+	}
+}
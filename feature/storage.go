@@ -0,0 +1,270 @@
+package feature
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+var (
+	// ErrObjectNotFound is returned when a storage key does not exist
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrInvalidObjectKey is returned for empty keys and keys escaping the storage root
+	ErrInvalidObjectKey = errors.New("invalid object key")
+	// ErrInvalidSignature is returned for tampered or expired signed URLs
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+	// SHA256 is the hex encoded SHA-256 of the content. Backends that do not
+	// keep metadata only return it from Put.
+	SHA256  string    `json:"sha256,omitempty"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// StorageBackend stores objects by key. Keys are slash separated relative paths.
+type StorageBackend interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (*ObjectInfo, error)
+	// Get returns the object content; the reader implements io.Seeker when the backend supports it
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// StorageService stores objects and issues signed, expiring download URLs
+type StorageService interface {
+	StorageBackend
+	// SignedURL returns a download URL for key valid for expiry (STORAGE_URL_EXPIRY when zero)
+	SignedURL(key string, expiry time.Duration) (string, error)
+	// VerifySignedURL checks the expires and signature query parameters of a signed URL for key
+	VerifySignedURL(key string, query url.Values) error
+	// URLPath is the path prefix signed URLs are served under
+	URLPath() string
+}
+
+type storageFeature struct {
+	config  *config.StorageConfig
+	backend StorageBackend
+}
+
+// NewStorageFeature creates the storage feature with the backend selected by STORAGE_DRIVER
+func NewStorageFeature() contracts.Features {
+	return &storageFeature{config: loadStorageConfig()}
+}
+
+// NewStorageFeatureWithBackend creates the storage feature with a custom backend
+func NewStorageFeatureWithBackend(backend StorageBackend) contracts.Features {
+	return &storageFeature{config: loadStorageConfig(), backend: backend}
+}
+
+func loadStorageConfig() *config.StorageConfig {
+	cfg := &config.StorageConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		log.Fatalf("Failed to load storage config: %v", err)
+	}
+
+	if cfg.Driver == "" {
+		cfg.Driver = config.StorageDriverLocal
+	}
+	if cfg.LocalDir == "" {
+		cfg.LocalDir = "./storage"
+	}
+	if cfg.URLPath == "" {
+		cfg.URLPath = "/files"
+	}
+	if cfg.URLExpiry == 0 {
+		cfg.URLExpiry = 15 * time.Minute
+	}
+	if cfg.S3Region == "" {
+		cfg.S3Region = "us-east-1"
+	}
+	return cfg
+}
+
+func (f *storageFeature) Name() string {
+	return "storage"
+}
+
+func (f *storageFeature) Setup(app contracts.App) error {
+	if err := f.config.Validate(); err != nil {
+		return fmt.Errorf("storage configuration validation failed: %w", err)
+	}
+
+	if f.backend == nil {
+		switch f.config.Driver {
+		case config.StorageDriverMemory:
+			f.backend = newMemoryStorage()
+		case config.StorageDriverS3:
+			f.backend = newS3Storage(f.config)
+		default:
+			backend, err := newLocalStorage(f.config.LocalDir)
+			if err != nil {
+				return err
+			}
+			f.backend = backend
+		}
+	}
+
+	storageSvc := &storageService{StorageBackend: f.backend, config: f.config}
+	if err := app.ProvideAs(storageSvc, (*StorageService)(nil)); err != nil {
+		return fmt.Errorf("failed to register StorageService: %w", err)
+	}
+	return nil
+}
+
+func (f *storageFeature) Close() error {
+	return nil
+}
+
+type storageService struct {
+	StorageBackend
+	config *config.StorageConfig
+}
+
+func (s *storageService) URLPath() string {
+	return s.config.URLPath
+}
+
+func (s *storageService) SignedURL(key string, expiry time.Duration) (string, error) {
+	if err := validateObjectKey(key); err != nil {
+		return "", err
+	}
+	if s.config.SigningKey == "" {
+		return "", fmt.Errorf("STORAGE_SIGNING_KEY is required for signed URLs")
+	}
+	if expiry <= 0 {
+		expiry = s.config.URLExpiry
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+
+	var escaped []string
+	for _, segment := range strings.Split(key, "/") {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return strings.TrimSuffix(s.config.PublicBaseURL, "/") + path.Join(s.config.URLPath, strings.Join(escaped, "/")) + "?" + query.Encode(), nil
+}
+
+func (s *storageService) VerifySignedURL(key string, query url.Values) error {
+	if s.config.SigningKey == "" {
+		return ErrInvalidSignature
+	}
+
+	expires := query.Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(s.sign(key, expires))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *storageService) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.SigningKey))
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateObjectKey rejects keys that are empty, absolute or contain "." or ".." segments
+func validateObjectKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidObjectKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidObjectKey
+		}
+	}
+	return nil
+}
+
+// hashingReader counts and hashes the bytes read through it
+type hashingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, hash: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.hash.Write(p[:n])
+	h.size += int64(n)
+	return n, err
+}
+
+func (h *hashingReader) Sum() string {
+	return hex.EncodeToString(h.hash.Sum(nil))
+}
+
+// inlineContentTypes are the stored types displayed in the browser; any other
+// type is downloaded, so uploaded HTML or SVG never runs on the app origin
+var inlineContentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/avif":      true,
+	"application/pdf": true,
+}
+
+func inlineContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && inlineContentTypes[mediaType]
+}
+
+// serveSignedFile serves the object of a signed storage URL
+func serveSignedFile(c *contracts.RequestContext, storage StorageService) (interface{}, bizerr.BizError) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := storage.VerifySignedURL(key, c.Request.URL.Query()); err != nil {
+		return nil, bizerr.CodeForbidden.WithError(err)
+	}
+
+	reader, info, err := storage.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) || errors.Is(err, ErrInvalidObjectKey) {
+			return nil, bizerr.ErrNotFound()
+		}
+		return nil, bizerr.ErrInternalServerError(err)
+	}
+
+	// Uploaded content is untrusted: scripts in it must not run even when displayed
+	c.Header("Content-Security-Policy", "sandbox")
+	c.Header("X-Content-Type-Options", "nosniff")
+	return &contracts.FileResponse{
+		Reader:      reader,
+		Name:        path.Base(key),
+		ContentType: info.ContentType,
+		ModTime:     info.ModTime,
+		Inline:      inlineContentType(info.ContentType),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package feature
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// memoryStorage keeps objects in memory, for tests and single instance setups
+type memoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string]*memoryObject
+}
+
+type memoryObject struct {
+	data []byte
+	info ObjectInfo
+}
+
+// memoryReader is a seekable in-memory object body
+type memoryReader struct {
+	*bytes.Reader
+}
+
+func (memoryReader) Close() error {
+	return nil
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: make(map[string]*memoryObject)}
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key string, r io.Reader, contentType string) (*ObjectInfo, error) {
+	if err := validateObjectKey(key); err != nil {
+		return nil, err
+	}
+
+	hr := newHashingReader(&contextReader{ctx: ctx, r: r})
+	data, err := io.ReadAll(hr)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &memoryObject{
+		data: data,
+		info: ObjectInfo{
+			Key:         key,
+			Size:        hr.size,
+			ContentType: contentType,
+			SHA256:      hr.Sum(),
+			ModTime:     time.Now(),
+		},
+	}
+
+	s.mu.Lock()
+	s.objects[key] = obj
+	s.mu.Unlock()
+
+	info := obj.info
+	return &info, nil
+}
+
+func (s *memoryStorage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	s.mu.RLock()
+	obj, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrObjectNotFound
+	}
+
+	info := obj.info
+	return memoryReader{bytes.NewReader(obj.data)}, &info, nil
+}
+
+func (s *memoryStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	s.mu.RLock()
+	obj, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	info := obj.info
+	return &info, nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return ErrObjectNotFound
+	}
+	delete(s.objects, key)
+	return nil
+}
@@ -0,0 +1,260 @@
+package feature
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shyandsy/aurora/config"
+)
+
+const (
+	s3MetaSHA256      = "X-Amz-Meta-Sha256"
+	s3EmptySHA256     = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3SignAlgorithm   = "AWS4-HMAC-SHA256"
+	s3TimeFormat      = "20060102T150405Z"
+	s3ShortTimeFormat = "20060102"
+)
+
+// s3Storage talks to an S3-compatible API, signing requests with AWS Signature Version 4
+type s3Storage struct {
+	config *config.StorageConfig
+	client *http.Client
+}
+
+func newS3Storage(cfg *config.StorageConfig) *s3Storage {
+	return &s3Storage{config: cfg, client: NewHTTPClient(0)}
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, r io.Reader, contentType string) (*ObjectInfo, error) {
+	if err := validateObjectKey(key); err != nil {
+		return nil, err
+	}
+
+	// The payload hash and length must be known before signing, so spool to disk
+	tmp, err := os.CreateTemp("", "aurora-s3-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hr := newHashingReader(&contextReader{ctx: ctx, r: r})
+	if _, err := io.Copy(tmp, hr); err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	sum := hr.Sum()
+	req, err := s.newRequest(ctx, http.MethodPut, key, io.NopCloser(tmp), sum)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = hr.size
+	req.Header.Set(s3MetaSHA256, sum)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, key)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &ObjectInfo{
+		Key:         key,
+		Size:        hr.size,
+		ContentType: contentType,
+		SHA256:      sum,
+		ModTime:     time.Now(),
+	}, nil
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	if err := validateObjectKey(key); err != nil {
+		return nil, nil, err
+	}
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, s3EmptySHA256)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := s.do(req, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, s.objectInfo(key, resp), nil
+}
+
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	if err := validateObjectKey(key); err != nil {
+		return nil, err
+	}
+	req, err := s.newRequest(ctx, http.MethodHead, key, nil, s3EmptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, key)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return s.objectInfo(key, resp), nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	if err := validateObjectKey(key); err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, s3EmptySHA256)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, key)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3Storage) objectInfo(key string, resp *http.Response) *ObjectInfo {
+	info := &ObjectInfo{
+		Key:         key,
+		ContentType: resp.Header.Get("Content-Type"),
+		SHA256:      resp.Header.Get(s3MetaSHA256),
+	}
+	info.Size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info
+}
+
+// objectURL returns the URL of key, path-style (endpoint/bucket/key) or
+// virtual-hosted style (bucket.endpoint/key)
+func (s *s3Storage) objectURL(key string) (*url.URL, error) {
+	endpoint, err := url.Parse(s.config.S3Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid STORAGE_S3_ENDPOINT: %w", err)
+	}
+
+	u := *endpoint
+	if s.config.S3PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.config.S3Bucket + "/" + key
+	} else {
+		u.Host = s.config.S3Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	return &u, nil
+}
+
+func (s *s3Storage) newRequest(ctx context.Context, method, key string, body io.ReadCloser, payloadHash string) (*http.Request, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	return req, nil
+}
+
+func (s *s3Storage) do(req *http.Request, key string) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 %s %s failed: %s %s", req.Method, key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 Authorization header to req.
+// X-Amz-Content-Sha256 must already be set.
+func (s *s3Storage) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format(s3TimeFormat)
+	shortDate := now.Format(s3ShortTimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := shortDate + "/" + s.config.S3Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3SignAlgorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.S3SecretKey), shortDate)
+	key = hmacSHA256(key, s.config.S3Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SignAlgorithm, s.config.S3AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath escapes every byte except unreserved characters and "/", as SigV4 requires
+func s3EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
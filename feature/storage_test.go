@@ -0,0 +1,219 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/config"
+)
+
+func newTestStorage() *storageService {
+	return &storageService{
+		StorageBackend: newMemoryStorage(),
+		config:         &config.StorageConfig{SigningKey: "secret", URLPath: "/files", URLExpiry: time.Minute},
+	}
+}
+
+func TestValidateObjectKey(t *testing.T) {
+	for key, valid := range map[string]bool{
+		"avatars/42.png":   true,
+		"a/b/c.txt":        true,
+		"":                 false,
+		"/etc/passwd":      false,
+		"../secret":        false,
+		"avatars/../../x":  false,
+		"avatars/./x":      false,
+		"avatars//x":       false,
+		"avatars/":         false,
+		`avatars\..\x`:     false,
+		"avatars/..":       false,
+		"avatars/.hidden":  true,
+		"avatars/a..b.png": true,
+	} {
+		if err := validateObjectKey(key); (err == nil) != valid {
+			t.Errorf("validateObjectKey(%q) = %v, want valid %v", key, err, valid)
+		}
+	}
+}
+
+func TestSignedURL(t *testing.T) {
+	s := newTestStorage()
+	signed, err := s.SignedURL("reports/q1 2024.csv", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != "/files/reports/q1 2024.csv" {
+		t.Errorf("path = %q", u.Path)
+	}
+	if err := s.VerifySignedURL("reports/q1 2024.csv", u.Query()); err != nil {
+		t.Errorf("valid URL rejected: %v", err)
+	}
+
+	expiresAt, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if until := time.Until(time.Unix(expiresAt, 0)); until <= 0 || until > time.Minute {
+		t.Errorf("expires in %s, want STORAGE_URL_EXPIRY", until)
+	}
+
+	tamper := func(name, value string) url.Values {
+		query := url.Values{}
+		for k, v := range u.Query() {
+			query[k] = v
+		}
+		query.Set(name, value)
+		return query
+	}
+	past := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	expired := url.Values{"expires": {past}, "signature": {s.sign("reports/q1 2024.csv", past)}}
+	tests := []struct {
+		name  string
+		key   string
+		query url.Values
+	}{
+		{"other key", "reports/q2 2024.csv", u.Query()},
+		{"extended expiry", "reports/q1 2024.csv", tamper("expires", strconv.FormatInt(expiresAt+3600, 10))},
+		{"forged signature", "reports/q1 2024.csv", tamper("signature", strings.Repeat("0", 64))},
+		{"missing signature", "reports/q1 2024.csv", tamper("signature", "")},
+		{"invalid expiry", "reports/q1 2024.csv", tamper("expires", "tomorrow")},
+		{"expired", "reports/q1 2024.csv", expired},
+	}
+	for _, tt := range tests {
+		if err := s.VerifySignedURL(tt.key, tt.query); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", tt.name, err)
+		}
+	}
+
+	if _, err := s.SignedURL("../secret", 0); !errors.Is(err, ErrInvalidObjectKey) {
+		t.Errorf("SignedURL of an invalid key: %v", err)
+	}
+	s.config.SigningKey = ""
+	if _, err := s.SignedURL("reports/q1 2024.csv", 0); err == nil {
+		t.Error("SignedURL without a signing key succeeded")
+	}
+	if err := s.VerifySignedURL("reports/q1 2024.csv", u.Query()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("URL verified without a signing key: %v", err)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	s, err := newLocalStorage(filepath.Join(root, "files"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	info, err := s.Put(ctx, "docs/readme.txt", strings.NewReader("hello"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != 5 || info.ContentType != "text/plain; charset=utf-8" || info.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Put = %+v", info)
+	}
+	reader, _, err := s.Get(ctx, "docs/readme.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reader.(io.Seeker); !ok {
+		t.Error("local objects are not seekable")
+	}
+	body, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(body) != "hello" {
+		t.Errorf("Get = %q", body)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "outside.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../outside.txt", "docs/../../outside.txt", "/outside.txt"} {
+		if _, _, err := s.Get(ctx, key); !errors.Is(err, ErrInvalidObjectKey) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidObjectKey", key, err)
+		}
+		if _, err := s.Put(ctx, key, strings.NewReader("x"), ""); !errors.Is(err, ErrInvalidObjectKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidObjectKey", key, err)
+		}
+	}
+
+	if _, err := s.Stat(ctx, "docs"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Stat of a directory: %v", err)
+	}
+	if err := s.Delete(ctx, "docs/readme.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "docs/readme.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("second Delete: %v", err)
+	}
+}
+
+func TestServeSignedFile(t *testing.T) {
+	t.Setenv("STORAGE_SIGNING_KEY", "secret")
+	a := newTestApp(t)
+	a.AddFeature(NewStorageFeatureWithBackend(newMemoryStorage()))
+	var storage StorageService
+	if err := a.Find(&storage); err != nil {
+		t.Fatal(err)
+	}
+	f := newTestServer(t, a, nil)
+
+	ctx := context.Background()
+	for key, contentType := range map[string]string{
+		"avatars/42.png":    "image/png",
+		"uploads/page.html": "text/html; charset=utf-8",
+		"uploads/logo.svg":  "image/svg+xml",
+	} {
+		if _, err := storage.Put(ctx, key, strings.NewReader("content"), contentType); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(key string) *httptest.ResponseRecorder {
+		t.Helper()
+		signed, err := storage.SignedURL(key, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return serve(f.Engine, httptest.NewRequest("GET", signed, nil))
+	}
+
+	tests := []struct {
+		key         string
+		disposition string
+	}{
+		{"avatars/42.png", `inline; filename=42.png`},
+		{"uploads/page.html", `attachment; filename=page.html`},
+		{"uploads/logo.svg", `attachment; filename=logo.svg`},
+	}
+	for _, tt := range tests {
+		w := get(tt.key)
+		if w.Code != http.StatusOK || w.Body.String() != "content" {
+			t.Errorf("%s: %d %q", tt.key, w.Code, w.Body.String())
+			continue
+		}
+		if w.Header().Get("Content-Disposition") != tt.disposition || w.Header().Get("Content-Security-Policy") != "sandbox" ||
+			w.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Errorf("%s: headers = %v", tt.key, w.Header())
+		}
+	}
+
+	if w := get("avatars/missing.png"); w.Code != http.StatusNotFound {
+		t.Errorf("missing object: status = %d", w.Code)
+	}
+	w := serve(f.Engine, httptest.NewRequest("GET", "/files/avatars/42.png?expires=9999999999&signature=forged", nil))
+	if w.Code != http.StatusForbidden || decodeBody(t, w)["code"] != bizerr.CodeForbidden.Code {
+		t.Errorf("forged URL: %d %s", w.Code, w.Body.String())
+	}
+}
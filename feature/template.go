@@ -0,0 +1,192 @@
+package feature
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/shyandsy/aurora/config"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// Templates under these directories are shared by all views: layouts wrap
+// views and partials are included with {{template "partials/..." .}}
+const (
+	templateLayoutDir  = "layouts/"
+	templatePartialDir = "partials/"
+)
+
+type templateFeature struct {
+	config *config.TemplateConfig
+	fsys   fs.FS
+	funcs  template.FuncMap
+	reload bool
+	views  map[string]*template.Template
+}
+
+// TemplateOption configures the template feature.
+type TemplateOption func(*templateFeature)
+
+// WithTemplateFS loads the templates from fsys, e.g. an embed.FS, instead of TEMPLATE_DIR.
+func WithTemplateFS(fsys fs.FS) TemplateOption {
+	return func(f *templateFeature) {
+		f.fsys = fsys
+	}
+}
+
+// WithTemplateFuncs adds functions to the templates.
+func WithTemplateFuncs(funcs template.FuncMap) TemplateOption {
+	return func(f *templateFeature) {
+		for name, fn := range funcs {
+			f.funcs[name] = fn
+		}
+	}
+}
+
+// NewTemplateFeature creates the feature rendering the contracts.View results of handlers
+// with html/template.
+func NewTemplateFeature(opts ...TemplateOption) contracts.Features {
+	cfg := &config.TemplateConfig{}
+	if err := config.ResolveConfig(cfg); err != nil {
+		log.Fatalf("Failed to load template config: %v", err)
+	}
+
+	if cfg.Dir == "" {
+		cfg.Dir = "templates"
+	}
+	if cfg.Extension == "" {
+		cfg.Extension = ".html"
+	}
+
+	f := &templateFeature{
+		config: cfg,
+		// T and lang are bound to the request when rendering
+		funcs: template.FuncMap{
+			"T":    func(id string, data ...interface{}) string { return id },
+			"lang": func() string { return "" },
+		},
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func (f *templateFeature) Name() string {
+	return "template"
+}
+
+func (f *templateFeature) Setup(app contracts.App) error {
+	if err := f.config.Validate(); err != nil {
+		return fmt.Errorf("template configuration validation failed: %w", err)
+	}
+
+	if f.fsys == nil {
+		f.fsys = os.DirFS(f.config.Dir)
+	}
+	f.reload = f.config.Reload || app.RunLevel() == config.RunLevelLocal
+
+	views, err := f.parse()
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	f.views = views
+
+	if err := app.ProvideAs(f, (*contracts.ViewRenderer)(nil)); err != nil {
+		return fmt.Errorf("failed to register ViewRenderer: %w", err)
+	}
+	return nil
+}
+
+func (f *templateFeature) Close() error {
+	return nil
+}
+
+// parse parses every view with the layouts and partials, so that each view
+// can define the blocks of the layouts
+func (f *templateFeature) parse() (map[string]*template.Template, error) {
+	var shared, pages []string
+	err := fs.WalkDir(f.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != f.config.Extension {
+			return err
+		}
+		if strings.HasPrefix(name, templateLayoutDir) || strings.HasPrefix(name, templatePartialDir) {
+			shared = append(shared, name)
+		} else {
+			pages = append(pages, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shared)
+
+	views := make(map[string]*template.Template, len(pages))
+	for _, page := range pages {
+		name := strings.TrimSuffix(page, f.config.Extension)
+		set := template.New(name).Funcs(f.funcs)
+		for _, file := range shared {
+			if err := f.parseFile(set.New(strings.TrimSuffix(file, f.config.Extension)), file); err != nil {
+				return nil, err
+			}
+		}
+		// The view is parsed last so that its definitions override the blocks of the layouts
+		if err := f.parseFile(set, page); err != nil {
+			return nil, err
+		}
+		views[name] = set
+	}
+	return views, nil
+}
+
+func (f *templateFeature) parseFile(t *template.Template, file string) error {
+	content, err := fs.ReadFile(f.fsys, file)
+	if err != nil {
+		return err
+	}
+	_, err = t.Parse(string(content))
+	return err
+}
+
+// Render executes the template of view, in its layout, with T and lang bound
+// to the language of the request
+func (f *templateFeature) Render(w io.Writer, c *contracts.RequestContext, view *contracts.View) error {
+	views := f.views
+	if f.reload {
+		var err error
+		if views, err = f.parse(); err != nil {
+			return err
+		}
+	}
+
+	set, ok := views[view.Name]
+	if !ok {
+		return fmt.Errorf("template %s not found", view.Name)
+	}
+	name := view.Name
+	layout := f.config.Layout
+	if view.Layout != "" {
+		layout = view.Layout
+	}
+	if layout != "" && !view.NoLayout {
+		if set.Lookup(layout) == nil {
+			return fmt.Errorf("layout %s not found", layout)
+		}
+		name = layout
+	}
+
+	// Executed templates cannot be cloned, so the parsed set is never executed
+	t, err := set.Clone()
+	if err != nil {
+		return err
+	}
+	t.Funcs(template.FuncMap{"T": c.T, "lang": c.GetLang})
+	return t.ExecuteTemplate(w, name, view.Data)
+}
@@ -0,0 +1,114 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+// mapTranslator translates the messages of a map per language
+type mapTranslator struct {
+	messages map[string]map[string]string
+}
+
+func (m *mapTranslator) T(id string, data ...interface{}) string {
+	return m.TWithLang("en", id, data...)
+}
+
+func (m *mapTranslator) TWithLang(lang, id string, data ...interface{}) string {
+	if message, ok := m.messages[lang][id]; ok {
+		return message
+	}
+	return id
+}
+
+func (m *mapTranslator) SetLang(lang string) {}
+
+func (m *mapTranslator) GetLang() string { return "en" }
+
+func (m *mapTranslator) SupportedLanguages() []string { return []string{"en", "fr"} }
+
+func TestTemplates(t *testing.T) {
+	t.Setenv("TEMPLATE_DIR", "templates")
+	t.Setenv("TEMPLATE_LAYOUT", "layouts/base")
+
+	a := newTestApp(t)
+	translator := &mapTranslator{messages: map[string]map[string]string{
+		"en": {"greeting": "Hello"},
+		"fr": {"greeting": "Bonjour"},
+	}}
+	if err := a.ProvideAs(translator, (*contracts.Translator)(nil)); err != nil {
+		t.Fatal(err)
+	}
+	a.AddFeature(NewTemplateFeature(WithTemplateFS(fstest.MapFS{
+		"layouts/base.html":  {Data: []byte(`<html lang="{{lang}}">{{template "partials/nav" .}}<main>{{block "content" .}}{{end}}</main></html>`)},
+		"layouts/plain.html": {Data: []byte(`<plain>{{block "content" .}}{{end}}</plain>`)},
+		"partials/nav.html":  {Data: []byte(`<nav>{{T "greeting"}}</nav>`)},
+		"home.html":          {Data: []byte(`{{define "content"}}{{T "greeting"}}, {{.Name}}{{end}}`)},
+		"auth/verified.html": {Data: []byte(`verified {{.}}`)},
+		"broken.html":        {Data: []byte(`{{define "content"}}{{.Missing.Field}}{{end}}`)},
+	})))
+
+	view := func(v *contracts.View) contracts.CustomizedHandlerFunc {
+		return func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return v, nil
+		}
+	}
+	f := newTestServer(t, a, []contracts.Route{
+		{Method: "GET", Path: "/home", Handler: view(contracts.NewView("home", map[string]string{"Name": "<b>Ann</b>"}))},
+		{Method: "GET", Path: "/plain", Handler: view(contracts.NewView("home", map[string]string{"Name": "Ann"}).WithLayout("layouts/plain"))},
+		{Method: "GET", Path: "/verified", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			return contracts.NewResponse(http.StatusCreated, &contracts.View{Name: "auth/verified", NoLayout: true, Data: "ann@example.com"}).
+				WithHeader("X-View", "verified"), nil
+		}},
+		{Method: "GET", Path: "/gone", Handler: view(contracts.NewView("home", map[string]string{"Name": "Ann"}).WithStatus(http.StatusGone))},
+		{Method: "GET", Path: "/missing", Handler: view(contracts.NewView("missing", nil))},
+		{Method: "GET", Path: "/broken", Handler: view(contracts.NewView("broken", 42))},
+	})
+
+	tests := []struct {
+		path           string
+		acceptLanguage string
+		status         int
+		body           string
+	}{
+		{"/home", "", http.StatusOK, `<html lang="en"><nav>Hello</nav><main>Hello, &lt;b&gt;Ann&lt;/b&gt;</main></html>`},
+		{"/home", "fr;q=0.9, en;q=0.8", http.StatusOK, `<html lang="fr"><nav>Bonjour</nav>`},
+		{"/home?lang=fr", "", http.StatusOK, `<html lang="fr"><nav>Bonjour</nav><main>Bonjour, &lt;b&gt;Ann&lt;/b&gt;</main></html>`},
+		{"/plain", "", http.StatusOK, `<plain>Hello, Ann</plain>`},
+		{"/verified", "", http.StatusCreated, `verified ann@example.com`},
+		{"/gone", "", http.StatusGone, `<html lang="en">`},
+		{"/missing", "", http.StatusInternalServerError, ""},
+		{"/broken", "", http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+		}
+		w := serve(f.Engine, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %q: status = %d, want %d: %s", tt.path, tt.acceptLanguage, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		if tt.status == http.StatusInternalServerError {
+			// Template errors are rendered by the error handler, without the partial output
+			if body := decodeBody(t, w); body["code"] != bizerr.CodeInternalServerError.Code {
+				t.Errorf("%s: body = %v", tt.path, body)
+			}
+			continue
+		}
+		if !strings.HasPrefix(w.Body.String(), tt.body) || w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Errorf("%s %q: %q %q, want %q", tt.path, tt.acceptLanguage, w.Header().Get("Content-Type"), w.Body.String(), tt.body)
+		}
+	}
+
+	if w := serve(f.Engine, httptest.NewRequest("GET", "/verified", nil)); w.Header().Get("X-View") != "verified" {
+		t.Errorf("response headers = %v", w.Header())
+	}
+}
@@ -0,0 +1,230 @@
+package feature
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+const (
+	// maxUploadFieldSize bounds each non-file form value of a multipart upload
+	maxUploadFieldSize = 1 << 20
+	// mimeSniffSize is how much of each file is read to detect its MIME type
+	mimeSniffSize = 3072
+)
+
+var errUploadTooLarge = errors.New("upload too large")
+
+// UploadOptions limits what SaveUploads accepts
+type UploadOptions struct {
+	// Fields lists the file fields to accept. Empty accepts any field.
+	Fields []string
+	// MaxFileSize is the maximum size of each file in bytes. Zero means no limit.
+	MaxFileSize int64
+	// MaxFiles is the maximum number of files. Zero means no limit.
+	MaxFiles int
+	// AllowedTypes lists accepted MIME types, detected from the content.
+	// Parent types match their children, e.g. "text/plain" accepts "text/csv",
+	// but also "text/html" and "image/svg+xml". Empty accepts any type.
+	AllowedTypes []string
+	// Prefix is prepended to the generated storage keys, e.g. "avatars/"
+	Prefix string
+}
+
+// UploadedFile is a file stored by SaveUploads
+type UploadedFile struct {
+	ObjectInfo
+	Field    string `json:"field"`
+	Filename string `json:"filename"`
+}
+
+// Upload is the result of SaveUploads
+type Upload struct {
+	Files  []*UploadedFile
+	Values url.Values
+}
+
+// File returns the first file uploaded in field
+func (u *Upload) File(field string) *UploadedFile {
+	for _, file := range u.Files {
+		if file.Field == field {
+			return file
+		}
+	}
+	return nil
+}
+
+// SaveUploads streams the files of a multipart request into storage, without
+// buffering them in memory or on disk. Files are checked against opts while
+// streaming; a violation removes the files stored so far and returns a
+// validation error for the offending field. Non-file fields are returned in Values.
+func SaveUploads(c *contracts.RequestContext, storage StorageService, opts UploadOptions) (*Upload, bizerr.BizError) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, bizerr.ErrBadRequest(fmt.Errorf("expected a multipart/form-data request"))
+	}
+
+	upload := &Upload{Values: url.Values{}}
+	success := false
+	defer func() {
+		if !success {
+			for _, file := range upload.Files {
+				_ = storage.Delete(c.Request.Context(), file.Key)
+			}
+		}
+	}()
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, multipartError(err)
+		}
+
+		field := part.FormName()
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxUploadFieldSize+1))
+			part.Close()
+			if err != nil {
+				return nil, multipartError(err)
+			}
+			if len(value) > maxUploadFieldSize {
+				return nil, bizerr.NewSingleFieldError(field, "value is too large")
+			}
+			upload.Values.Add(field, string(value))
+			continue
+		}
+
+		if len(opts.Fields) > 0 && !containsString(opts.Fields, field) {
+			part.Close()
+			return nil, bizerr.NewSingleFieldError(field, "unexpected file field")
+		}
+		if opts.MaxFiles > 0 && len(upload.Files) >= opts.MaxFiles {
+			part.Close()
+			return nil, bizerr.NewSingleFieldError(field, fmt.Sprintf("at most %d files are allowed", opts.MaxFiles))
+		}
+
+		file, bizErr := saveUploadPart(c, storage, part, opts)
+		part.Close()
+		if bizErr != nil {
+			return nil, bizErr
+		}
+		upload.Files = append(upload.Files, file)
+	}
+
+	success = true
+	return upload, nil
+}
+
+func saveUploadPart(c *contracts.RequestContext, storage StorageService, part *multipart.Part, opts UploadOptions) (*UploadedFile, bizerr.BizError) {
+	field := part.FormName()
+
+	head := make([]byte, mimeSniffSize)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, contracts.BodyError(err)
+	}
+	head = head[:n]
+
+	detected := mimetype.Detect(head)
+	if len(opts.AllowedTypes) > 0 && !mimeAllowed(detected, opts.AllowedTypes) {
+		return nil, bizerr.NewSingleFieldError(field, fmt.Sprintf("file type %s is not allowed", detected.String()))
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), part)
+	if opts.MaxFileSize > 0 {
+		body = &limitedReader{r: body, remaining: opts.MaxFileSize}
+	}
+
+	key := opts.Prefix + randomHex(16) + detected.Extension()
+	info, err := storage.Put(c.Request.Context(), key, body, detected.String())
+	if err != nil {
+		if errors.Is(err, errUploadTooLarge) {
+			_ = storage.Delete(c.Request.Context(), key)
+			return nil, bizerr.NewSingleFieldError(field, fmt.Sprintf("file exceeds the maximum size of %d bytes", opts.MaxFileSize))
+		}
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = storage.Delete(c.Request.Context(), key)
+			return nil, contracts.BodyError(err)
+		}
+		return nil, bizerr.ErrInternalServerError(fmt.Errorf("failed to store upload: %w", err))
+	}
+
+	return &UploadedFile{
+		ObjectInfo: *info,
+		Field:      field,
+		Filename:   path.Base(strings.ReplaceAll(part.FileName(), "\\", "/")),
+	}, nil
+}
+
+// multipartError reports a body over the route size limit with 413, and other errors with 400
+func multipartError(err error) bizerr.BizError {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return contracts.BodyError(err)
+	}
+	return bizerr.ErrBadRequest(fmt.Errorf("invalid multipart body: %w", err))
+}
+
+func mimeAllowed(detected *mimetype.MIME, allowed []string) bool {
+	for m := detected; m != nil; m = m.Parent() {
+		for _, allowedType := range allowed {
+			if m.Is(allowedType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// limitedReader fails with errUploadTooLarge once more than remaining bytes are read
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errUploadTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errUploadTooLarge
+	}
+	return n, err
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,134 @@
+package feature
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+type uploadPart struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+func multipartRequest(t *testing.T, parts ...uploadPart) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range parts {
+		var err error
+		if part.filename == "" {
+			err = mw.WriteField(part.field, string(part.content))
+		} else {
+			var fw io.Writer
+			fw, err = mw.CreateFormFile(part.field, part.filename)
+			if err == nil {
+				_, err = fw.Write(part.content)
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestSaveUploads(t *testing.T) {
+	storage := newTestStorage()
+	f := newTestServer(t, newTestApp(t), []contracts.Route{
+		{Method: "POST", Path: "/upload", Handler: func(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+			upload, err := SaveUploads(c, storage, UploadOptions{
+				Fields:       []string{"avatar", "attachment"},
+				MaxFiles:     2,
+				MaxFileSize:  64,
+				AllowedTypes: []string{"image/png", "text/csv"},
+				Prefix:       "uploads/",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return upload, nil
+		}},
+	})
+	countObjects := func() int {
+		storage.StorageBackend.(*memoryStorage).mu.RLock()
+		defer storage.StorageBackend.(*memoryStorage).mu.RUnlock()
+		return len(storage.StorageBackend.(*memoryStorage).objects)
+	}
+
+	w := serve(f.Engine, multipartRequest(t,
+		uploadPart{field: "title", content: []byte("Profile")},
+		uploadPart{field: "avatar", filename: `C:\photos\me.png`, content: pngHeader},
+		uploadPart{field: "attachment", filename: "notes.csv", content: []byte("id,total\n1,7\n")},
+	))
+	if w.Code != http.StatusOK {
+		t.Fatalf("upload: %d %s", w.Code, w.Body.String())
+	}
+	body := decodeBody(t, w)
+	files, _ := body["Files"].([]interface{})
+	if len(files) != 2 || body["Values"].(map[string]interface{})["title"].([]interface{})[0] != "Profile" {
+		t.Fatalf("upload = %v", body)
+	}
+	avatar := files[0].(map[string]interface{})
+	if avatar["field"] != "avatar" || avatar["filename"] != "me.png" || avatar["content_type"] != "image/png" ||
+		!strings.HasPrefix(avatar["key"].(string), "uploads/") || !strings.HasSuffix(avatar["key"].(string), ".png") {
+		t.Errorf("avatar = %v", avatar)
+	}
+	if countObjects() != 2 {
+		t.Errorf("stored %d objects, want 2", countObjects())
+	}
+
+	tests := []struct {
+		name  string
+		parts []uploadPart
+		field string
+	}{
+		{"HTML disguised as an image", []uploadPart{{field: "avatar", filename: "me.png", content: []byte("<html><script>alert(1)</script></html>")}}, "avatar"},
+		{"SVG", []uploadPart{{field: "avatar", filename: "me.png", content: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)}}, "avatar"},
+		{"unexpected field", []uploadPart{{field: "other", filename: "me.png", content: pngHeader}}, "other"},
+		{"too large", []uploadPart{{field: "attachment", filename: "big.csv", content: append([]byte("id,total\n"), bytes.Repeat([]byte("1,7\n"), 16)...)}}, "attachment"},
+		{"too many files", []uploadPart{
+			{field: "avatar", filename: "a.png", content: pngHeader},
+			{field: "avatar", filename: "b.png", content: pngHeader},
+			{field: "avatar", filename: "c.png", content: pngHeader},
+		}, "avatar"},
+	}
+	for _, tt := range tests {
+		before := countObjects()
+		w := serve(f.Engine, multipartRequest(t, tt.parts...))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400: %s", tt.name, w.Code, w.Body.String())
+			continue
+		}
+		body := decodeBody(t, w)
+		if fields, _ := body["fields"].(map[string]interface{}); body["code"] != bizerr.CodeValidationFailed.Code || fields[tt.field] == nil {
+			t.Errorf("%s: body = %v", tt.name, body)
+		}
+		// Files stored before the violation are removed
+		if countObjects() != before {
+			t.Errorf("%s: %d objects left, want %d", tt.name, countObjects(), before)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/upload", strings.NewReader(`{"avatar":"me.png"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if w := serve(f.Engine, req); w.Code != http.StatusBadRequest || decodeBody(t, w)["code"] != bizerr.CodeBadRequest.Code {
+		t.Errorf("JSON body: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+// accessLogger writes access log records as they are, without prefix, so that they stay parseable
+var accessLogger = log.New(os.Stdout, "", 0)
+
+// Access writes an access log record (always logged, independently of the log level)
+func Access(record string) {
+	accessLogger.Print(record)
+}
+
+// SetAccessOutput redirects the access log, e.g. to a file
+func SetAccessOutput(w io.Writer) {
+	accessLogger.SetOutput(w)
+}
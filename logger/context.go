@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// withRequestID prefixes the message with the request ID of ctx, if any
+func withRequestID(ctx context.Context, format string, v ...interface{}) string {
+	msg := fmt.Sprintf(format, v...)
+	if requestID := RequestID(ctx); requestID != "" {
+		return "[request_id=" + requestID + "] " + msg
+	}
+	return msg
+}
+
+// ErrorContext logs an error message tagged with the request ID of ctx (always logged)
+func ErrorContext(ctx context.Context, format string, v ...interface{}) {
+	errorLogger.Output(2, withRequestID(ctx, format, v...))
+}
+
+// InfoContext logs an info message tagged with the request ID of ctx (logged when level is Info or Debug)
+func InfoContext(ctx context.Context, format string, v ...interface{}) {
+	if currentLogLevel <= LogLevelInfo {
+		infoLogger.Output(2, withRequestID(ctx, format, v...))
+	}
+}
+
+// DebugContext logs a debug message tagged with the request ID of ctx (only logged when level is Debug)
+func DebugContext(ctx context.Context, format string, v ...interface{}) {
+	if currentLogLevel <= LogLevelDebug {
+		debugLogger.Output(2, withRequestID(ctx, format, v...))
+	}
+}
@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed swagger.html
+var swaggerHTML string
+
+var swaggerTemplate = template.Must(template.New("swagger").Parse(swaggerHTML))
+
+// swaggerUIFiles holds the Swagger UI distribution files served by
+// SwaggerUIAssetsHandler, so the page works without access to a CDN.
+//
+//go:embed swaggerui/swagger-ui.css swaggerui/swagger-ui-bundle.js
+var swaggerUIFiles embed.FS
+
+// Handler serves the JSON encoding of doc.
+func Handler(doc *Document) (gin.HandlerFunc, error) {
+	data, err := doc.JSON()
+	if err != nil {
+		return nil, err
+	}
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}, nil
+}
+
+// SwaggerUIHandler serves a Swagger UI page that loads the document from specURL
+// and the Swagger UI assets from assetsURL, where SwaggerUIAssetsHandler is routed.
+func SwaggerUIHandler(title, specURL, assetsURL string) (gin.HandlerFunc, error) {
+	var buf bytes.Buffer
+	if err := swaggerTemplate.Execute(&buf, map[string]string{
+		"Title":     title,
+		"SpecURL":   specURL,
+		"AssetsURL": assetsURL,
+	}); err != nil {
+		return nil, err
+	}
+	page := buf.Bytes()
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}, nil
+}
+
+// SwaggerUIAssetsHandler serves the embedded Swagger UI assets. It must be
+// routed with a *filepath parameter, e.g. "/docs/assets/*filepath".
+func SwaggerUIAssetsHandler() gin.HandlerFunc {
+	assets, err := fs.Sub(swaggerUIFiles, "swaggerui")
+	if err != nil {
+		panic(err)
+	}
+	files := http.FS(assets)
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.FileFromFS(c.Param("filepath"), files)
+	}
+}
@@ -0,0 +1,277 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/contracts"
+)
+
+const (
+	bearerScheme    = "bearerAuth"
+	errorSchemaName = "Error"
+	errorSchemaRef  = "#/components/schemas/" + errorSchemaName
+	jsonContentType = "application/json"
+)
+
+// Options configures document generation.
+type Options struct {
+	Info    Info
+	Servers []Server
+
+	// IsSecured reports whether a route middleware enforces authentication,
+	// for routes that do not set contracts.Route.Secured. Optional.
+	IsSecured func(gin.HandlerFunc) bool
+
+	// ErrorSchema overrides the schema of error responses. Set it when a
+	// custom contracts.ErrorHandler renders a different JSON shape.
+	ErrorSchema *Schema
+}
+
+// Generate builds an OpenAPI document from registered routes.
+func Generate(routes []contracts.Route, opts Options) *Document {
+	registry := newSchemaRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    opts.Info,
+		Servers: opts.Servers,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	secured := false
+	for _, r := range routes {
+		method := strings.ToLower(r.Method)
+		path := convertPath(r.Path)
+
+		op := buildOperation(registry, r, path)
+		op.Deprecated = r.Deprecation != nil
+		if isSecured(r, opts.IsSecured) {
+			op.Security = []map[string][]string{{bearerScheme: {}}}
+			op.Responses[strconv.Itoa(http.StatusUnauthorized)] = errorResponse("Unauthorized")
+			op.Responses[strconv.Itoa(http.StatusForbidden)] = errorResponse("Forbidden")
+			secured = true
+		}
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[method] = op
+	}
+
+	errorSchema := opts.ErrorSchema
+	if errorSchema == nil {
+		errorSchema = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"code":       {Type: "string"},
+				"message":    {Type: "string"},
+				"request_id": {Type: "string"},
+				"fields":     {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			},
+			Required: []string{"code", "message"},
+		}
+	}
+	registry.schemas[errorSchemaName] = errorSchema
+
+	doc.Components = &Components{Schemas: registry.schemas}
+	if secured {
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+	}
+
+	return doc
+}
+
+// JSON returns the indented JSON encoding of the document.
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func buildOperation(registry *schemaRegistry, r contracts.Route, path string) *Operation {
+	op := &Operation{
+		OperationID: operationID(r.Method, r.Path),
+		Summary:     r.Summary,
+		Description: r.Description,
+		Tags:        r.Tags,
+		Responses:   make(map[string]*Response),
+	}
+
+	op.Parameters = pathParameters(path)
+	hasInput := len(op.Parameters) > 0
+
+	if r.Request != nil {
+		reqType := indirectType(reflect.TypeOf(r.Request))
+		if reqType.Kind() == reflect.Struct {
+			mergeParameters(op, structParameters(registry, reqType, hasBody(r.Method)))
+		}
+		if hasBody(r.Method) {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content: map[string]*MediaType{
+					jsonContentType: {Schema: registry.schemaFor(reqType)},
+				},
+			}
+		}
+		hasInput = true
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if r.Response != nil && status != http.StatusNoContent {
+		success.Content = map[string]*MediaType{
+			jsonContentType: {Schema: registry.schemaFor(reflect.TypeOf(r.Response))},
+		}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	if hasInput {
+		op.Responses[strconv.Itoa(http.StatusBadRequest)] = errorResponse("Bad request")
+	}
+	if len(pathParameters(path)) > 0 {
+		op.Responses[strconv.Itoa(http.StatusNotFound)] = errorResponse("Not found")
+	}
+	op.Responses[strconv.Itoa(http.StatusInternalServerError)] = errorResponse("Internal server error")
+
+	return op
+}
+
+// structParameters collects `uri`, `header` and (for methods without a body)
+// `form` tagged fields of a request struct.
+func structParameters(registry *schemaRegistry, t reflect.Type, withBody bool) []*Parameter {
+	var params []*Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		in, name := "", ""
+		if tag := field.Tag.Get("uri"); tag != "" {
+			in, name = "path", tag
+		} else if tag := field.Tag.Get("header"); tag != "" {
+			in, name = "header", tag
+		} else if tag := field.Tag.Get("form"); tag != "" && !withBody {
+			in, name = "query", tag
+		}
+		name = strings.Split(name, ",")[0]
+		if in == "" || name == "" || name == "-" {
+			continue
+		}
+
+		schema := registry.schemaFor(field.Type)
+		required := applyBinding(schema, field.Type, bindingRules(field))
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          in,
+			Description: field.Tag.Get("description"),
+			Required:    required || in == "path",
+			Schema:      schema,
+		})
+	}
+	return params
+}
+
+// mergeParameters adds params to op, replacing path parameters of the same name.
+func mergeParameters(op *Operation, params []*Parameter) {
+	for _, p := range params {
+		replaced := false
+		for i, existing := range op.Parameters {
+			if existing.In == p.In && existing.Name == p.Name {
+				op.Parameters[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			op.Parameters = append(op.Parameters, p)
+		}
+	}
+}
+
+// pathParameters returns the parameters of an OpenAPI-style path.
+func pathParameters(path string) []*Parameter {
+	var params []*Parameter
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params = append(params, &Parameter{
+				Name:     seg[1 : len(seg)-1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+	return params
+}
+
+// convertPath turns gin path parameters (:id, *path) into OpenAPI templates ({id}).
+func convertPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '.'
+	}) {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			b.WriteString("By")
+			seg = seg[1:]
+		}
+		if seg == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(seg[:1]) + seg[1:])
+	}
+	return b.String()
+}
+
+func hasBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+func errorResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Content: map[string]*MediaType{
+			jsonContentType: {Schema: &Schema{Ref: errorSchemaRef}},
+		},
+	}
+}
+
+func isSecured(r contracts.Route, match func(gin.HandlerFunc) bool) bool {
+	if r.Secured {
+		return true
+	}
+	if match == nil {
+		return false
+	}
+	for _, mw := range r.Middlewares {
+		if match(mw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+type testCreateReq struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	Role     string `json:"role" binding:"oneof=admin user"`
+	Internal string `json:"-"`
+}
+
+type testListReq struct {
+	Page     int    `form:"page" binding:"min=1"`
+	Keyword  string `form:"keyword"`
+	TenantID string `header:"X-Tenant-ID" binding:"required"`
+}
+
+type testCustomer struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags"`
+}
+
+func testHandler(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+	return nil, nil
+}
+
+func testJWTMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) { c.Next() }
+}
+
+// TestConvertPath tests gin to OpenAPI path conversion
+func TestConvertPath(t *testing.T) {
+	cases := map[string]string{
+		"/api/user":            "/api/user",
+		"/api/user/:id":        "/api/user/{id}",
+		"/files/*filepath":     "/files/{filepath}",
+		"/a/:id/b/:sub_id/c":   "/a/{id}/b/{sub_id}/c",
+		"/role-feature/:id/do": "/role-feature/{id}/do",
+	}
+	for in, want := range cases {
+		if got := convertPath(in); got != want {
+			t.Errorf("convertPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestGenerate_RequestBodyAndSchemas tests request body and component schemas
+func TestGenerate_RequestBodyAndSchemas(t *testing.T) {
+	doc := Generate([]contracts.Route{
+		{
+			Method:   "POST",
+			Path:     "/customer",
+			Handler:  testHandler,
+			Request:  testCreateReq{},
+			Response: &testCustomer{},
+		},
+	}, Options{Info: Info{Title: "test", Version: "1.0.0"}})
+
+	op := (*doc.Paths["/customer"])["post"]
+	if op == nil {
+		t.Fatal("POST /customer operation missing")
+	}
+	if op.RequestBody == nil {
+		t.Fatal("request body missing")
+	}
+	if ref := op.RequestBody.Content[jsonContentType].Schema.Ref; ref != "#/components/schemas/testCreateReq" {
+		t.Errorf("request body ref = %q", ref)
+	}
+
+	req := doc.Components.Schemas["testCreateReq"]
+	if req == nil {
+		t.Fatal("testCreateReq schema missing")
+	}
+	if len(req.Required) != 2 || req.Required[0] != "email" || req.Required[1] != "password" {
+		t.Errorf("required = %v, want [email password]", req.Required)
+	}
+	if req.Properties["email"].Format != "email" {
+		t.Errorf("email format = %q, want email", req.Properties["email"].Format)
+	}
+	if min := req.Properties["password"].MinLength; min == nil || *min != 6 {
+		t.Errorf("password minLength = %v, want 6", min)
+	}
+	if enum := req.Properties["role"].Enum; len(enum) != 2 {
+		t.Errorf("role enum = %v, want 2 values", enum)
+	}
+	if _, ok := req.Properties["Internal"]; ok {
+		t.Error("json:\"-\" field should be skipped")
+	}
+
+	customer := doc.Components.Schemas["testCustomer"]
+	if customer == nil {
+		t.Fatal("testCustomer schema missing")
+	}
+	if customer.Properties["created_at"].Format != "date-time" {
+		t.Errorf("created_at format = %q, want date-time", customer.Properties["created_at"].Format)
+	}
+	if customer.Properties["tags"].Items == nil || customer.Properties["tags"].Items.Type != "string" {
+		t.Error("tags should be an array of strings")
+	}
+
+	for _, code := range []string{"200", "400", "500"} {
+		if _, ok := op.Responses[code]; !ok {
+			t.Errorf("response %s missing", code)
+		}
+	}
+	if _, ok := doc.Components.Schemas[errorSchemaName]; !ok {
+		t.Error("Error schema missing")
+	}
+}
+
+// TestGenerate_Parameters tests path, query and header parameters
+func TestGenerate_Parameters(t *testing.T) {
+	doc := Generate([]contracts.Route{
+		{Method: "GET", Path: "/customer", Handler: testHandler, Request: testListReq{}},
+		{Method: "GET", Path: "/customer/:id", Handler: testHandler},
+	}, Options{})
+
+	list := (*doc.Paths["/customer"])["get"]
+	if list.RequestBody != nil {
+		t.Error("GET should not have a request body")
+	}
+	params := map[string]*Parameter{}
+	for _, p := range list.Parameters {
+		params[p.In+":"+p.Name] = p
+	}
+	if p := params["query:page"]; p == nil || p.Schema.Type != "integer" || p.Schema.Minimum == nil {
+		t.Errorf("query page parameter = %+v", p)
+	}
+	if p := params["header:X-Tenant-ID"]; p == nil || !p.Required {
+		t.Errorf("header X-Tenant-ID parameter = %+v", p)
+	}
+
+	get := (*doc.Paths["/customer/{id}"])["get"]
+	if len(get.Parameters) != 1 || get.Parameters[0].Name != "id" || !get.Parameters[0].Required {
+		t.Errorf("path parameters = %+v", get.Parameters)
+	}
+	if _, ok := get.Responses["404"]; !ok {
+		t.Error("404 response missing for path with parameters")
+	}
+	if get.OperationID != "getCustomerById" {
+		t.Errorf("operationId = %q, want getCustomerById", get.OperationID)
+	}
+}
+
+// TestGenerate_Security tests security requirements of secured routes
+func TestGenerate_Security(t *testing.T) {
+	routes := []contracts.Route{
+		{Method: "GET", Path: "/public", Handler: testHandler},
+		{Method: "GET", Path: "/private", Handler: testHandler, Middlewares: []gin.HandlerFunc{testJWTMiddleware()}, Secured: true},
+		// Middlewares are not matched by name
+		{Method: "GET", Path: "/unmarked", Handler: testHandler, Middlewares: []gin.HandlerFunc{testJWTMiddleware()}},
+	}
+	doc := Generate(routes, Options{})
+
+	for _, path := range []string{"/public", "/unmarked"} {
+		if op := (*doc.Paths[path])["get"]; len(op.Security) != 0 {
+			t.Errorf("%s should not require security", path)
+		}
+	}
+	op := (*doc.Paths["/private"])["get"]
+	if len(op.Security) != 1 {
+		t.Fatal("private route should require bearer security")
+	}
+	if _, ok := op.Responses["401"]; !ok {
+		t.Error("401 response missing for secured route")
+	}
+	if doc.Components.SecuritySchemes[bearerScheme] == nil {
+		t.Error("bearer security scheme missing")
+	}
+
+	// IsSecured detects the middlewares of unmarked routes
+	doc = Generate(routes, Options{IsSecured: func(gin.HandlerFunc) bool { return true }})
+	for path, secured := range map[string]bool{"/public": false, "/private": true, "/unmarked": true} {
+		if op := (*doc.Paths[path])["get"]; (len(op.Security) == 1) != secured {
+			t.Errorf("%s: security = %v, want secured %v", path, op.Security, secured)
+		}
+	}
+}
+
+// TestGenerate_Versions tests inherited versioned routes and deprecation
+func TestGenerate_Versions(t *testing.T) {
+	doc := Generate(contracts.Versions("/api",
+		contracts.APIVersion{
+			Name:        "v1",
+			Deprecation: &contracts.Deprecation{},
+			Routes: []contracts.Route{
+				{Method: "GET", Path: "/users", Handler: testHandler},
+				{Method: "GET", Path: "/legacy", Handler: testHandler},
+			},
+		},
+		contracts.APIVersion{
+			Name: "v2",
+			Routes: []contracts.Route{
+				{Method: "GET", Path: "/legacy"},
+			},
+		},
+	), Options{})
+
+	for path, deprecated := range map[string]bool{"/api/v1/users": true, "/api/v1/legacy": true, "/api/v2/users": false} {
+		item, ok := doc.Paths[path]
+		if !ok {
+			t.Fatalf("path %s missing", path)
+		}
+		if got := (*item)["get"].Deprecated; got != deprecated {
+			t.Errorf("%s deprecated = %v, want %v", path, got, deprecated)
+		}
+	}
+	if _, ok := doc.Paths["/api/v2/legacy"]; ok {
+		t.Error("route removed in v2 should not be inherited")
+	}
+}
+
+// TestGenerate_SuccessStatus tests the documented success status of routes
+func TestGenerate_SuccessStatus(t *testing.T) {
+	doc := Generate([]contracts.Route{
+		{Method: "GET", Path: "/customer", Handler: testHandler, Response: []testCustomer{}},
+		{Method: "POST", Path: "/customer", Handler: testHandler, Response: testCustomer{}, Status: http.StatusCreated},
+		{Method: "DELETE", Path: "/customer/:id", Handler: testHandler, Response: testCustomer{}, Status: http.StatusNoContent},
+	}, Options{})
+
+	cases := []struct {
+		path, method, status string
+		content              bool
+	}{
+		{"/customer", "get", "200", true},
+		{"/customer", "post", "201", true},
+		{"/customer/{id}", "delete", "204", false},
+	}
+	for _, tc := range cases {
+		op := (*doc.Paths[tc.path])[tc.method]
+		resp, ok := op.Responses[tc.status]
+		if !ok {
+			t.Errorf("%s %s: response %s missing", tc.method, tc.path, tc.status)
+			continue
+		}
+		if (resp.Content != nil) != tc.content {
+			t.Errorf("%s %s: content = %v", tc.method, tc.path, resp.Content)
+		}
+		if tc.status != "200" {
+			if _, ok := op.Responses["200"]; ok {
+				t.Errorf("%s %s: unexpected 200 response", tc.method, tc.path)
+			}
+		}
+	}
+}
+
+// TestSwaggerUIHandler tests that Swagger UI is served without a CDN
+func TestSwaggerUIHandler(t *testing.T) {
+	ui, err := SwaggerUIHandler("test", "/openapi.json", "/docs/assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := gin.New()
+	engine.GET("/docs", ui)
+	engine.GET("/docs/assets/*filepath", SwaggerUIAssetsHandler())
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/docs", nil))
+	page := w.Body.String()
+	if w.Code != http.StatusOK || strings.Contains(page, "https://") || !strings.Contains(page, `src="/docs/assets/swagger-ui-bundle.js"`) {
+		t.Errorf("page = %d %s", w.Code, page)
+	}
+
+	for path, contentType := range map[string]string{
+		"/docs/assets/swagger-ui.css":       "text/css",
+		"/docs/assets/swagger-ui-bundle.js": "javascript",
+	} {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK || w.Body.Len() == 0 || !strings.Contains(w.Header().Get("Content-Type"), contentType) {
+			t.Errorf("%s: %d %q", path, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/docs/assets/missing.js", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing asset status = %d", w.Code)
+	}
+}
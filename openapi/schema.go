@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaRegistry converts Go types into schemas and collects named struct
+// schemas so they can be referenced from components.
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema of t. Named structs are registered as components
+// and returned as a $ref.
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	t = indirectType(t)
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		name := r.register(t)
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	for i := 2; ; i++ {
+		if _, taken := r.schemas[name]; !taken {
+			break
+		}
+		name = t.Name() + strconv.Itoa(i)
+	}
+
+	// Reserve the name before walking fields so recursive types terminate.
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(schema, t)
+	return schema
+}
+
+func (r *schemaRegistry) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		// Embedded structs without a json name are flattened, as encoding/json does.
+		if field.Anonymous && name == "" && indirectType(field.Type).Kind() == reflect.Struct {
+			r.addFields(schema, indirectType(field.Type))
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := r.schemaFor(field.Type)
+		rules := bindingRules(field)
+		if applyBinding(prop, field.Type, rules) {
+			schema.Required = append(schema.Required, name)
+		}
+		if doc := field.Tag.Get("description"); doc != "" {
+			prop.Description = doc
+		}
+		schema.Properties[name] = prop
+	}
+}
+
+// jsonName returns the JSON property name of a field and whether it is skipped.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+// bindingRules splits the gin `binding` tag (validator syntax) into rules.
+func bindingRules(field reflect.StructField) []string {
+	tag := field.Tag.Get("binding")
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// applyBinding maps validator rules onto schema constraints and reports
+// whether the field is required. Constraints are not applied to $ref schemas.
+func applyBinding(schema *Schema, t reflect.Type, rules []string) bool {
+	required := false
+	for _, rule := range rules {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if key == "dive" {
+			break
+		}
+		if key == "required" {
+			required = true
+			continue
+		}
+		if schema.Ref != "" {
+			continue
+		}
+
+		switch key {
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "datetime":
+			schema.Format = "date-time"
+		case "oneof":
+			for _, v := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, enumValue(schema.Type, v))
+			}
+		case "min", "gte":
+			setLimit(schema, t, value, true)
+		case "max", "lte":
+			setLimit(schema, t, value, false)
+		case "len":
+			setLimit(schema, t, value, true)
+			setLimit(schema, t, value, false)
+		}
+	}
+	return required
+}
+
+func setLimit(schema *Schema, t reflect.Type, value string, lower bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		v := int(n)
+		if lower {
+			schema.MinLength = &v
+		} else {
+			schema.MaxLength = &v
+		}
+	case "array":
+		v := int(n)
+		if lower {
+			schema.MinItems = &v
+		} else {
+			schema.MaxItems = &v
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+	}
+}
+
+func enumValue(schemaType, v string) interface{} {
+	switch schemaType {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
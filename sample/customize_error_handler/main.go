@@ -18,18 +18,23 @@ type CustomErrorHandler struct{}
 
 func (CustomErrorHandler) HandleError(c *gin.Context, err error) {
 	code := http.StatusInternalServerError
-	msg := err.Error()
-	if e, ok := err.(bizerr.BizError); ok {
+	msg := http.StatusText(code)
+	var e bizerr.BizError
+	if errors.As(err, &e) {
 		code = e.HTTPCode()
 		msg = e.Message()
 	}
-	c.JSON(code, gin.H{
+	body := gin.H{
 		"code":      code,
 		"message":   msg,
-		"error":     err.Error(),
 		"timestamp": time.Now().Unix(),
 		"custom":    true,
-	})
+	}
+	// Causes of server errors stay in the logs
+	if code < http.StatusInternalServerError {
+		body["error"] = err.Error()
+	}
+	c.JSON(code, body)
 }
 
 func main() {
@@ -55,6 +60,11 @@ func main() {
 			Path:    "/err-bad-request",
 			Handler: handleErrBadRequest,
 		},
+		{
+			Method:  "GET",
+			Path:    "/panic",
+			Handler: handlePanic,
+		},
 	})
 
 	if err := a.Run(); err != nil {
@@ -73,3 +83,7 @@ func handleErr(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
 func handleErrBadRequest(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
 	return nil, bizerr.ErrBadRequest(errors.New("bad request for test"))
 }
+
+func handlePanic(c *contracts.RequestContext) (interface{}, bizerr.BizError) {
+	panic("intended panic for test")
+}
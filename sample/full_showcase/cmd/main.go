@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/shyandsy/aurora/app"
@@ -21,9 +22,21 @@ func main() {
 	// Create app (no Mail feature)
 	a := app.NewApp()
 
-	// Add features
+	// Add the server and register routes
 	server := auroraFeature.NewServerFeature()
 	a.AddFeature(server)
+	a.RegisterRoutes(controller.GetRoutes(a))
+
+	// Run "openapi" and "errors" commands before connecting to the database
+	if ok, err := a.RunCommand(os.Args[1:]); ok {
+		if err != nil {
+			logger.Errorf("command failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Add features
 	a.AddFeature(auroraFeature.NewGormFeature())
 	a.AddFeature(auroraFeature.NewRedisFeature())
 	a.AddFeature(auroraFeature.NewJWTFeature())
@@ -39,9 +52,6 @@ func main() {
 	// Register DI providers
 	registerProviders(a)
 
-	// Register routes
-	a.RegisterRoutes(controller.GetRoutes(a))
-
 	// Run server (blocks until shutdown)
 	if err := a.Run(); err != nil {
 		logger.Errorf("app run failed: %v", err)
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	auroraFeature "github.com/shyandsy/aurora/feature"
+)
+
+// GetUserID extracts user ID from gin.Context (set by feature.JWTAuthMiddleware)
+func GetUserID(c *gin.Context) (int64, bool) {
+	userID, exists := c.Get(auroraFeature.ContextKeyUserID)
+	if !exists {
+		return 0, false
+	}
+
+	id, ok := userID.(int64)
+	return id, ok
+}
+
+// GetUserEmail extracts user email from gin.Context (set by feature.JWTAuthMiddleware)
+func GetUserEmail(c *gin.Context) (string, bool) {
+	email, exists := c.Get(auroraFeature.ContextKeyUserEmail)
+	if !exists {
+		return "", false
+	}
+
+	emailStr, ok := email.(string)
+	return emailStr, ok
+}
@@ -3,7 +3,7 @@ package controller
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/shyandsy/aurora/contracts"
-	"github.com/shyandsy/aurora/sample/full_showcase/common/middleware"
+	auroraFeature "github.com/shyandsy/aurora/feature"
 	"github.com/shyandsy/aurora/sample/full_showcase/controller/auth"
 	"github.com/shyandsy/aurora/sample/full_showcase/controller/customer"
 	"github.com/shyandsy/aurora/sample/full_showcase/controller/feature"
@@ -30,131 +30,152 @@ func GetRoutes(app contracts.App) []contracts.Route {
 			Method:      "GET",
 			Path:        apiPrefix + "/user",
 			Handler:     user.GetUsers,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "user.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "user.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/user/:id",
 			Handler:     user.GetUser,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "user.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "user.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "POST",
 			Path:        apiPrefix + "/user",
 			Handler:     user.CreateUser,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "user.create")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "user.create")},
+			Secured:     true,
 		},
 		{
 			Method:      "PUT",
 			Path:        apiPrefix + "/user/:id",
 			Handler:     user.UpdateUser,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "user.update")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "user.update")},
+			Secured:     true,
 		},
 		{
 			Method:      "DELETE",
 			Path:        apiPrefix + "/user/:id",
 			Handler:     user.DeleteUser,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "user.delete")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "user.delete")},
+			Secured:     true,
 		},
 		// Role routes (JWT required with feature check)
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/role",
 			Handler:     role.GetRoles,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "role.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "role.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/role/:id",
 			Handler:     role.GetRole,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "role.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "role.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "POST",
 			Path:        apiPrefix + "/role",
 			Handler:     role.CreateRole,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "role.create")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "role.create")},
+			Secured:     true,
 		},
 		{
 			Method:      "PUT",
 			Path:        apiPrefix + "/role/:id",
 			Handler:     role.UpdateRole,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "role.update")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "role.update")},
+			Secured:     true,
 		},
 		{
 			Method:      "DELETE",
 			Path:        apiPrefix + "/role/:id",
 			Handler:     role.DeleteRole,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "role.delete")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "role.delete")},
+			Secured:     true,
 		},
 		// Feature routes (JWT required with feature check)
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/feature",
 			Handler:     feature.GetFeatures,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "feature.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "feature.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/feature/:id",
 			Handler:     feature.GetFeature,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "feature.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "feature.get")},
+			Secured:     true,
 		},
 		// RoleFeature routes (JWT required with feature check)
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/role-feature",
 			Handler:     role_feature.GetRoleFeatures,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "rolefeature.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "rolefeature.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/role-feature/:id",
 			Handler:     role_feature.GetRoleFeature,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "rolefeature.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "rolefeature.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "POST",
 			Path:        apiPrefix + "/role-feature",
 			Handler:     role_feature.CreateRoleFeature,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "rolefeature.create")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "rolefeature.create")},
+			Secured:     true,
 		},
 		{
 			Method:      "DELETE",
 			Path:        apiPrefix + "/role-feature/:id",
 			Handler:     role_feature.DeleteRoleFeature,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "rolefeature.delete")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "rolefeature.delete")},
+			Secured:     true,
 		},
 		// Customer routes (JWT required with feature check)
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/customer",
 			Handler:     customer.GetCustomers,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "customer.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "customer.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "GET",
 			Path:        apiPrefix + "/customer/:id",
 			Handler:     customer.GetCustomer,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "customer.get")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "customer.get")},
+			Secured:     true,
 		},
 		{
 			Method:      "POST",
 			Path:        apiPrefix + "/customer",
 			Handler:     customer.CreateCustomer,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "customer.create")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "customer.create")},
+			Secured:     true,
 		},
 		{
 			Method:      "PUT",
 			Path:        apiPrefix + "/customer/:id",
 			Handler:     customer.UpdateCustomer,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "customer.update")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "customer.update")},
+			Secured:     true,
 		},
 		{
 			Method:      "DELETE",
 			Path:        apiPrefix + "/customer/:id",
 			Handler:     customer.DeleteCustomer,
-			Middlewares: []gin.HandlerFunc{middleware.JWTAuthMiddleware(app, "customer.delete")},
+			Middlewares: []gin.HandlerFunc{auroraFeature.JWTAuthMiddleware(app, "customer.delete")},
+			Secured:     true,
 		},
 	}
 }
@@ -0,0 +1,252 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// Broker fans broadcasts out to the hubs of other instances.
+type Broker interface {
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe calls fn for every published payload until ctx is done.
+	Subscribe(ctx context.Context, fn func(payload []byte)) error
+}
+
+// HubOptions configures a Hub.
+type HubOptions struct {
+	// PingInterval is the keepalive ping interval. A connection that sends
+	// nothing (pongs included) for two intervals is closed.
+	PingInterval time.Duration
+}
+
+// brokerMessage is the payload published to the broker.
+type brokerMessage struct {
+	Origin  string                     `json:"origin"`
+	Room    string                     `json:"room,omitempty"`
+	Message contracts.WebSocketMessage `json:"message"`
+}
+
+// Hub tracks sessions and their rooms.
+type Hub struct {
+	opts       HubOptions
+	instanceID string
+	broker     Broker
+
+	mu       sync.RWMutex
+	sessions map[*Session]struct{}
+	rooms    map[string]map[*Session]struct{}
+	closed   bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ contracts.WebSocketHub = (*Hub)(nil)
+
+func NewHub(opts HubOptions) *Hub {
+	if opts.PingInterval <= 0 {
+		opts.PingInterval = 30 * time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Hub{
+		opts:       opts,
+		instanceID: randomID(),
+		sessions:   make(map[*Session]struct{}),
+		rooms:      make(map[string]map[*Session]struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Run enables cross-instance broadcasts through broker and subscribes to it
+// until the hub is closed. A nil broker keeps broadcasts local.
+func (h *Hub) Run(broker Broker) {
+	if broker == nil {
+		return
+	}
+
+	h.mu.Lock()
+	h.broker = broker
+	h.mu.Unlock()
+
+	go func() {
+		for h.ctx.Err() == nil {
+			err := broker.Subscribe(h.ctx, h.receive)
+			if err != nil && h.ctx.Err() == nil {
+				logger.Error("websocket broker subscription failed: %v", err)
+				select {
+				case <-h.ctx.Done():
+				case <-time.After(time.Second):
+				}
+			}
+		}
+	}()
+}
+
+// Broadcast sends a message to every connection.
+func (h *Hub) Broadcast(msgType string, data interface{}) error {
+	return h.BroadcastToRoom("", msgType, data)
+}
+
+// BroadcastToRoom sends a message to the connections in room. An empty room
+// means every connection.
+func (h *Hub) BroadcastToRoom(room, msgType string, data interface{}) error {
+	msg, err := newMessage(msgType, "", data)
+	if err != nil {
+		return err
+	}
+
+	h.deliver(room, msg)
+
+	h.mu.RLock()
+	broker := h.broker
+	h.mu.RUnlock()
+	if broker == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(brokerMessage{Origin: h.instanceID, Room: room, Message: msg})
+	if err != nil {
+		return err
+	}
+	return broker.Publish(h.ctx, payload)
+}
+
+// Count returns the number of local connections.
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.sessions)
+}
+
+// Close closes every connection with "going away" and stops the broker subscription.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	sessions := make([]*Session, 0, len(h.sessions))
+	for s := range h.sessions {
+		sessions = append(sessions, s)
+	}
+	h.mu.Unlock()
+
+	h.cancel()
+	for _, s := range sessions {
+		_ = s.conn.Close(CloseGoingAway, "server shutting down")
+	}
+}
+
+func (h *Hub) receive(payload []byte) {
+	var msg brokerMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		logger.Error("websocket broker: invalid message: %v", err)
+		return
+	}
+	if msg.Origin == h.instanceID {
+		return
+	}
+	h.deliver(msg.Room, msg.Message)
+}
+
+func (h *Hub) deliver(room string, msg contracts.WebSocketMessage) {
+	h.mu.RLock()
+	targets := make([]*Session, 0, len(h.sessions))
+	if room == "" {
+		for s := range h.sessions {
+			targets = append(targets, s)
+		}
+	} else {
+		for s := range h.rooms[room] {
+			targets = append(targets, s)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, s := range targets {
+		if err := s.conn.WriteJSON(msg); err != nil {
+			logger.DebugContext(s.ctx, "websocket %s: broadcast failed: %v", s.id, err)
+		}
+	}
+}
+
+func (h *Hub) register(s *Session) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.sessions[s] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(s *Session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, s)
+	for room := range s.rooms {
+		h.leaveLocked(s, room)
+	}
+}
+
+func (h *Hub) join(s *Session, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.sessions[s]; !ok {
+		return
+	}
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*Session]struct{})
+	}
+	h.rooms[room][s] = struct{}{}
+	s.rooms[room] = struct{}{}
+}
+
+func (h *Hub) leave(s *Session, room string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.leaveLocked(s, room)
+}
+
+func (h *Hub) leaveLocked(s *Session, room string) {
+	delete(s.rooms, room)
+	if members, ok := h.rooms[room]; ok {
+		delete(members, s)
+		if len(members) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+}
+
+func (h *Hub) sessionRooms(s *Session) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	rooms := make([]string, 0, len(s.rooms))
+	for room := range s.rooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+func newMessage(msgType, id string, data interface{}) (contracts.WebSocketMessage, error) {
+	msg := contracts.WebSocketMessage{Type: msgType, ID: id}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return msg, err
+		}
+		msg.Data = raw
+	}
+	return msg, nil
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,218 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+	"github.com/shyandsy/aurora/logger"
+)
+
+// Session is a connection registered with a Hub.
+type Session struct {
+	id    string
+	conn  *Conn
+	hub   *Hub
+	rooms map[string]struct{} // guarded by hub.mu
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ contracts.WebSocketConn = (*Session)(nil)
+
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Send sends a message of msgType with data as payload.
+func (s *Session) Send(msgType string, data interface{}) error {
+	msg, err := newMessage(msgType, "", data)
+	if err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(msg)
+}
+
+func (s *Session) Join(room string) {
+	s.hub.join(s, room)
+}
+
+func (s *Session) Leave(room string) {
+	s.hub.leave(s, room)
+}
+
+func (s *Session) Rooms() []string {
+	return s.hub.sessionRooms(s)
+}
+
+func (s *Session) Close() error {
+	return s.conn.Close(CloseNormal, "")
+}
+
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// Serve upgrades the request and handles the connection until it closes.
+// A rejected handshake is returned as an error before anything is written.
+func (h *Hub) Serve(c *contracts.RequestContext, route contracts.WebSocketRoute, upgrader *Upgrader) bizerr.BizError {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request)
+	if err != nil {
+		var handshakeErr *HandshakeError
+		if errors.As(err, &handshakeErr) {
+			return bizerr.New(handshakeErr.Status, errors.New(handshakeErr.Message))
+		}
+		return bizerr.New(http.StatusInternalServerError, err)
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	s := &Session{
+		id:     randomID(),
+		conn:   conn,
+		hub:    h,
+		rooms:  make(map[string]struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	defer cancel()
+
+	if !h.register(s) {
+		_ = conn.Close(CloseGoingAway, "server shutting down")
+		return nil
+	}
+	defer func() {
+		h.unregister(s)
+		_ = conn.Close(CloseNormal, "")
+	}()
+
+	go s.keepAlive(h.opts.PingInterval)
+	conn.SetReadTimeout(2 * h.opts.PingInterval)
+
+	if route.OnConnect != nil {
+		if bizErr := route.OnConnect(c, s); bizErr != nil {
+			s.sendError(c, "", bizErr)
+			_ = conn.Close(ClosePolicyViolation, bizErr.Message())
+			return nil
+		}
+	}
+	if route.OnDisconnect != nil {
+		defer route.OnDisconnect(c, s)
+	}
+
+	for {
+		opcode, data, err := conn.ReadMessage()
+		if err != nil {
+			var closeErr *CloseError
+			if !errors.Is(err, ErrClosed) && !errors.As(err, &closeErr) {
+				logger.DebugContext(s.ctx, "websocket %s: read failed: %v", s.id, err)
+			}
+			return nil
+		}
+
+		if opcode != OpText {
+			s.sendError(c, "", bizerr.New(http.StatusBadRequest, errors.New("only text messages are supported")))
+			continue
+		}
+
+		var msg contracts.WebSocketMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			s.sendError(c, "", bizerr.New(http.StatusBadRequest, errors.New("invalid message")))
+			continue
+		}
+
+		s.dispatch(c, route.Handlers, msg)
+	}
+}
+
+// dispatch runs the handler for msg and sends its result or error back.
+func (s *Session) dispatch(c *contracts.RequestContext, handlers map[string]contracts.WebSocketHandlerFunc, msg contracts.WebSocketMessage) {
+	handler, ok := handlers[msg.Type]
+	if !ok {
+		s.sendError(c, msg.ID, bizerr.New(http.StatusNotFound, fmt.Errorf("unknown message type %q", msg.Type)))
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ErrorContext(s.ctx, "websocket %s: panic handling %q: %v\n%s", s.id, msg.Type, r, debug.Stack())
+			s.sendError(c, msg.ID, bizerr.ErrInternalServerError(fmt.Errorf("panic handling %q: %v", msg.Type, r)))
+		}
+	}()
+
+	result, bizErr := handler(c, s, msg.Data)
+	if bizErr != nil {
+		s.sendError(c, msg.ID, bizErr)
+		return
+	}
+	if result == nil {
+		return
+	}
+
+	reply, err := newMessage(msg.Type, msg.ID, result)
+	if err != nil {
+		logger.ErrorContext(s.ctx, "websocket %s: failed to encode reply to %q: %v", s.id, msg.Type, err)
+		s.sendError(c, msg.ID, bizerr.ErrInternalServerError(fmt.Errorf("failed to encode reply: %w", err)))
+		return
+	}
+	_ = s.conn.WriteJSON(reply)
+}
+
+// sendError sends bizErr as an "error" message, with the catalogue code and
+// the translated message as in HTTP error responses.
+func (s *Session) sendError(c *contracts.RequestContext, id string, bizErr bizerr.BizError) {
+	_ = s.conn.WriteJSON(contracts.WebSocketMessage{
+		Type: "error",
+		ID:   id,
+		Error: &contracts.WebSocketError{
+			Status:  bizErr.HTTPCode(),
+			Code:    bizErr.Code(),
+			Message: c.ErrorMessage(bizErr),
+		},
+	})
+}
+
+// keepAlive pings the client until the connection closes. Missing pongs are
+// detected by the read timeout.
+func (s *Session) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.conn.Done():
+			return
+		case <-ticker.C:
+			if err := s.conn.Ping(time.Now().Add(interval / 2)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Handle adapts a typed handler to contracts.WebSocketHandlerFunc. The message
+// data is decoded into T and validated with its binding tags.
+func Handle[T any](fn func(c *contracts.RequestContext, conn contracts.WebSocketConn, msg T) (interface{}, bizerr.BizError)) contracts.WebSocketHandlerFunc {
+	return func(c *contracts.RequestContext, conn contracts.WebSocketConn, data json.RawMessage) (interface{}, bizerr.BizError) {
+		var msg T
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return nil, bizerr.New(http.StatusBadRequest, fmt.Errorf("invalid message data: %w", err))
+			}
+		}
+		if binding.Validator != nil {
+			if err := binding.Validator.ValidateStruct(&msg); err != nil {
+				return nil, bizerr.New(http.StatusBadRequest, err)
+			}
+		}
+		return fn(c, conn, msg)
+	}
+}
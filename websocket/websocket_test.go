@@ -0,0 +1,262 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shyandsy/aurora/bizerr"
+	"github.com/shyandsy/aurora/contracts"
+)
+
+type testClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dial(t *testing.T, url string) *testClient {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	req := "GET /ws HTTP/1.1\r\nHost: " + strings.TrimPrefix(url, "http://") + "\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Sec-WebSocket-Accept = %q", got)
+	}
+	return &testClient{conn: conn, br: br}
+}
+
+func (c *testClient) send(t *testing.T, v interface{}) {
+	t.Helper()
+
+	payload, _ := json.Marshal(v)
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | OpText, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *testClient) read(t *testing.T) contracts.WebSocketMessage {
+	t.Helper()
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(c.br, header[:]); err != nil {
+			t.Fatal(err)
+		}
+		length := int(header[1] & 0x7F)
+		if length == 126 {
+			var ext [2]byte
+			io.ReadFull(c.br, ext[:])
+			length = int(binary.BigEndian.Uint16(ext[:]))
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			t.Fatal(err)
+		}
+		if header[0]&0x0F != OpText {
+			continue
+		}
+
+		var msg contracts.WebSocketMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+}
+
+// testTranslator translates the messages of its map, whatever the language
+type testTranslator map[string]string
+
+func (t testTranslator) T(id string, data ...interface{}) string {
+	return t.TWithLang("en", id, data...)
+}
+
+func (t testTranslator) TWithLang(lang, id string, data ...interface{}) string {
+	if message, ok := t[id]; ok {
+		return message
+	}
+	return id
+}
+
+func (t testTranslator) SetLang(lang string) {}
+
+func (t testTranslator) GetLang() string { return "en" }
+
+func (t testTranslator) SupportedLanguages() []string { return []string{"en"} }
+
+type echoRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+func newTestServer(t *testing.T) (*Hub, string) {
+	gin.SetMode(gin.TestMode)
+	hub := NewHub(HubOptions{PingInterval: time.Minute})
+	route := contracts.WebSocketRoute{
+		Path: "/ws",
+		Handlers: map[string]contracts.WebSocketHandlerFunc{
+			"echo": Handle(func(c *contracts.RequestContext, conn contracts.WebSocketConn, msg echoRequest) (interface{}, bizerr.BizError) {
+				return msg, nil
+			}),
+			"join": Handle(func(c *contracts.RequestContext, conn contracts.WebSocketConn, room string) (interface{}, bizerr.BizError) {
+				conn.Join(room)
+				return conn.Rooms(), nil
+			}),
+			"fail": func(c *contracts.RequestContext, conn contracts.WebSocketConn, data json.RawMessage) (interface{}, bizerr.BizError) {
+				return nil, bizerr.ErrForbidden()
+			},
+			"panic": func(c *contracts.RequestContext, conn contracts.WebSocketConn, data json.RawMessage) (interface{}, bizerr.BizError) {
+				panic("database is gone")
+			},
+		},
+	}
+
+	engine := gin.New()
+	engine.GET("/ws", func(c *gin.Context) {
+		if err := hub.Serve(&contracts.RequestContext{Context: c, Translator: testTranslator{"error.forbidden": "Access denied"}}, route, &Upgrader{}); err != nil {
+			c.JSON(err.HTTPCode(), gin.H{"message": err.Message()})
+		}
+	})
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	t.Cleanup(hub.Close)
+	return hub, server.URL
+}
+
+func TestServeDispatchesMessages(t *testing.T) {
+	_, url := newTestServer(t)
+	client := dial(t, url)
+
+	client.send(t, map[string]interface{}{"type": "echo", "id": "1", "data": map[string]string{"text": "hi"}})
+	if msg := client.read(t); msg.Type != "echo" || msg.ID != "1" || string(msg.Data) != `{"text":"hi"}` {
+		t.Fatalf("echo reply = %+v", msg)
+	}
+
+	tests := []struct {
+		msgType string
+		data    interface{}
+		err     contracts.WebSocketError
+	}{
+		{"echo", map[string]string{}, contracts.WebSocketError{Status: http.StatusBadRequest, Code: bizerr.CodeBadRequest.Code}},
+		// Catalogue messages are translated
+		{"fail", nil, contracts.WebSocketError{Status: http.StatusForbidden, Code: bizerr.CodeForbidden.Code, Message: "Access denied"}},
+		{"missing", nil, contracts.WebSocketError{Status: http.StatusNotFound, Code: bizerr.CodeNotFound.Code}},
+		// Panics keep their cause on the server
+		{"panic", nil, contracts.WebSocketError{Status: http.StatusInternalServerError, Code: bizerr.CodeInternalServerError.Code, Message: "internal server error"}},
+	}
+	for i, tt := range tests {
+		id := strconv.Itoa(i + 2)
+		client.send(t, map[string]interface{}{"type": tt.msgType, "id": id, "data": tt.data})
+		msg := client.read(t)
+		if msg.Type != "error" || msg.ID != id || msg.Error == nil || msg.Error.Status != tt.err.Status || msg.Error.Code != tt.err.Code ||
+			(tt.err.Message != "" && msg.Error.Message != tt.err.Message) {
+			t.Errorf("%s reply = %+v, error %+v", tt.msgType, msg, msg.Error)
+		}
+	}
+
+	// The session is still served after a panic
+	client.send(t, map[string]interface{}{"type": "echo", "id": "9", "data": map[string]string{"text": "again"}})
+	if msg := client.read(t); msg.Type != "echo" || msg.ID != "9" {
+		t.Fatalf("echo after panic = %+v", msg)
+	}
+}
+
+func TestHubBroadcastToRoom(t *testing.T) {
+	hub, url := newTestServer(t)
+	member := dial(t, url)
+	other := dial(t, url)
+
+	member.send(t, map[string]interface{}{"type": "join", "data": "news"})
+	if msg := member.read(t); string(msg.Data) != `["news"]` {
+		t.Fatalf("join reply = %+v", msg)
+	}
+	if hub.Count() != 2 {
+		t.Fatalf("Count() = %d, want 2", hub.Count())
+	}
+
+	if err := hub.BroadcastToRoom("news", "headline", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if msg := member.read(t); msg.Type != "headline" || string(msg.Data) != `"hello"` {
+		t.Fatalf("room broadcast = %+v", msg)
+	}
+
+	if err := hub.Broadcast("all", 1); err != nil {
+		t.Fatal(err)
+	}
+	if msg := other.read(t); msg.Type != "all" {
+		t.Fatalf("broadcast = %+v", msg)
+	}
+}
+
+func TestHubCloseSendsGoingAway(t *testing.T) {
+	hub, url := newTestServer(t)
+	client := dial(t, url)
+
+	deadline := time.Now().Add(time.Second)
+	for hub.Count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.Close()
+
+	_ = client.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var header [2]byte
+	if _, err := io.ReadFull(client.br, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, header[1]&0x7F)
+	if _, err := io.ReadFull(client.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	if header[0]&0x0F != OpClose || binary.BigEndian.Uint16(payload) != CloseGoingAway {
+		t.Fatalf("got frame %x %x, want close 1001", header, payload)
+	}
+}
+
+func TestUpgradeRejectsPlainRequest(t *testing.T) {
+	_, url := newTestServer(t)
+
+	resp, err := http.Get(url + "/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+}